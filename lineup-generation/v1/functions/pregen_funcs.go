@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"sort"
+	"math"
 	"io"
 	"bytes"
 	"sync"
@@ -145,48 +146,64 @@ func GetAvailableSlots(players []Player, day int, week string) map[string]Player
 		}
 	}
 
-	// Find most restrictive positions for players playing
-	optimal_slotting := func (playing []Player) map[string]Player {
+	// Create response map and fill with the matched lineup or empty strings for unused positions except for bench spots
+	lineup := MatchPlayers(playing, position_order)
+	response := make(map[string]Player)
+	filter := map[string]bool{"BE1": true, "BE2": true, "BE3": true}
+	for _, pos := range position_order {
 
-		sort.Slice(playing, func(i, j int) bool {
-			return len(playing[i].ValidPositions) < len(playing[j].ValidPositions)
-		})
-
-		// Create struct to keep track of state across recursive function calls
-		max_score := CalculateMaxScore(playing)
-		p_context := &FitPlayersContext{
-			BestLineup: make(map[string]Player), 
-			TopScore: 0, 
-			MaxScore: max_score, 
-			EarlyExit: false,
+		if value, ok := lineup[pos]; ok {
+			response[pos] = value
+			continue
 		}
-	
-		// Recursive function call
-		FitPlayers(playing, make(map[string]Player), position_order, p_context, 0)
-	
-		// Create response map and fill with best lineup or empty strings for unused positions except for bench spots
-		response := make(map[string]Player)
-		filter := map[string]bool{"BE1": true, "BE2": true, "BE3": true}
-		for _, pos := range position_order {
+		if _, ok := filter[pos]; !ok {
+			response[pos] = Player{}
+		}
+	}
+
+	return response
+}
+
+// Function to slot players as a maximum weight bipartite matching between players and positions.
+// Restrictiveness always comes first, ties are broken by putting the best players in starting positions
+func MatchPlayers(players []Player, position_order []string) map[string]Player {
+
+	score_map := GetScoreMap()
+	filter := map[string]bool{"BE1": true, "BE2": true, "BE3": true}
+
+	// Scale restrictiveness past the total points of every player so points can only break ties
+	scale := 1.0
+	for _, player := range players {
+		scale += max(player.AvgPoints, 0)
+	}
 
-			if value, ok := p_context.BestLineup[pos]; ok {
-				response[pos] = value
+	weights := make([][]float64, len(players))
+	for i, player := range players {
+		weights[i] = make([]float64, len(position_order))
+		for j, pos := range position_order {
+			if !Contains(player.ValidPositions, pos) {
+				weights[i][j] = math.Inf(-1)
 				continue
 			}
-			if _, ok := filter[pos]; !ok {
-				response[pos] = Player{}
+			weights[i][j] = float64(score_map[pos]) * scale
+			if !filter[pos] {
+				weights[i][j] += max(player.AvgPoints, 0)
 			}
 		}
+	}
 
-		return response
-	
-	}(playing)
-
-	return optimal_slotting
+	lineup := make(map[string]Player)
+	for i, j := range MaxWeightAssignment(weights) {
+		if j >= 0 {
+			lineup[position_order[j]] = players[i]
+		}
+	}
 
+	return lineup
 }
 
-// Recursive backtracking function to find most restrictive positions for players
+// Recursive backtracking function to find most restrictive positions for players. Superseded by MatchPlayers
+// for daily slotting and kept as a reference for tests and benchmarks
 func FitPlayers(players []Player, cur_lineup map[string]Player, position_order []string, ctx *FitPlayersContext, index int) {
 
 	// If we have found a lineup that has the max score, we can send returns to all other recursive calls
@@ -240,8 +257,8 @@ func FitPlayers(players []Player, cur_lineup map[string]Player, position_order [
 	}
 }
 
-// Function to score a roster based on restricitveness of positions
-func ScoreRoster(roster map[string]Player) int {
+// Function to get the restrictiveness score of each position
+func GetScoreMap() map[string]int {
 
 	// Scoring system
 	score_map := make(map[string]int)
@@ -253,6 +270,14 @@ func ScoreRoster(roster map[string]Player) int {
 		}
 	}
 
+	return score_map
+}
+
+// Function to score a roster based on restricitveness of positions
+func ScoreRoster(roster map[string]Player) int {
+
+	score_map := GetScoreMap()
+
 	// Score roster
 	score := 0
 	for pos := range roster {
@@ -1,16 +0,0 @@
-import requests
-
-def dowload_json(url, output_file):
-    response = requests.get(url)
-    if response.status_code == 200:
-        with open(output_file, 'w') as f:
-            f.write(response.text)
-        print('Downloaded successfully')
-    else:
-        print('Download failed')
-
-json_url = "https://cdn.nba.com/static/json/staticData/scheduleLeagueV2.json"
-
-output_file_path = "/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v1/static/schedule_raw2025-2026.json"
-
-dowload_json(json_url, output_file_path)
\ No newline at end of file
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	. "lineup-generation/v1/functions"
+	"sort"
+	"testing"
+)
+
+// Players for a full day, with ESPN style valid positions that include the flex and bench slots
+func slottingPlayers() []Player {
+	flex := []string{"UT1", "UT2", "UT3", "BE1", "BE2", "BE3"}
+	positions := func(listed ...string) []string {
+		return append(listed, flex...)
+	}
+	return []Player{
+		{Name: "Guard 1", AvgPoints: 48.0, ValidPositions: positions("PG", "SG", "G")},
+		{Name: "Guard 2", AvgPoints: 41.0, ValidPositions: positions("PG", "G")},
+		{Name: "Guard 3", AvgPoints: 36.0, ValidPositions: positions("SG", "G")},
+		{Name: "Wing 1", AvgPoints: 39.0, ValidPositions: positions("SG", "SF", "G", "F")},
+		{Name: "Forward 1", AvgPoints: 45.0, ValidPositions: positions("SF", "PF", "F")},
+		{Name: "Forward 2", AvgPoints: 30.0, ValidPositions: positions("PF", "C", "F")},
+		{Name: "Center 1", AvgPoints: 52.0, ValidPositions: positions("C")},
+		{Name: "Center 2", AvgPoints: 25.0, ValidPositions: positions("PF", "C")},
+		{Name: "Center 3", AvgPoints: 18.0, ValidPositions: positions("C")},
+		{Name: "Center 4", AvgPoints: 60.0, ValidPositions: positions("C")},
+	}
+}
+
+var slotting_order = []string{"PG", "SG", "SF", "PF", "G", "F", "C", "UT1", "UT2", "UT3", "BE1", "BE2", "BE3"}
+
+// Function to slot players with the original backtracking search
+func fitPlayers(players []Player) *FitPlayersContext {
+	sorted := append([]Player{}, players...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i].ValidPositions) < len(sorted[j].ValidPositions)
+	})
+	ctx := &FitPlayersContext{BestLineup: make(map[string]Player), MaxScore: CalculateMaxScore(sorted)}
+	FitPlayers(sorted, make(map[string]Player), slotting_order, ctx, 0)
+	return ctx
+}
+
+func TestMatchPlayers(t *testing.T) {
+	players := slottingPlayers()
+
+	// Every prefix of the day's players should reach the same restrictiveness score as the recursion
+	for n := 1; n <= len(players); n++ {
+		matched := MatchPlayers(players[:n], slotting_order)
+		ctx := fitPlayers(players[:n])
+		if len(matched) != n {
+			t.Errorf("%d players: expected every player slotted, got %d", n, len(matched))
+		}
+		if score := ScoreRoster(matched); score != ctx.TopScore {
+			t.Errorf("%d players: expected score %d, got %d", n, ctx.TopScore, score)
+		}
+	}
+}
+
+func BenchmarkMatchPlayers(b *testing.B) {
+	players := slottingPlayers()
+	for i := 0; i < b.N; i++ {
+		MatchPlayers(players, slotting_order)
+	}
+}
+
+func BenchmarkFitPlayers(b *testing.B) {
+	players := slottingPlayers()
+	for i := 0; i < b.N; i++ {
+		fitPlayers(players)
+	}
+}
@@ -0,0 +1,78 @@
+// Copy of v3/helpers/acquisitions.go, only the package name differs. Each version is its own module, so the copies are
+// kept in sync by TestSharedFilesInSync in v3/tests/shared_files_test.go
+
+package data
+
+import "fmt"
+
+// Value for an acquisition rule the league doesn't have
+const NoLimit = -1
+
+// Number of days a dropped player has to clear waivers before he can be picked up again
+const DefaultWaiverDays = 3
+
+// Struct for a league's transaction rules. The per day cap and season budget are NoLimit when the league doesn't use them
+type AcquisitionLimits struct {
+	PerWeek    int
+	PerDay     int
+	Season     int
+	WaiverDays int
+
+	// Acquisitions already made this week before the plan starts, they count against the weekly limit
+	UsedThisWeek int
+}
+
+// Function to get the limits used when the request has none, one acquisition for each day of the week
+func DefaultAcquisitionLimits(game_span int) AcquisitionLimits {
+	return AcquisitionLimits{PerWeek: game_span, PerDay: NoLimit, Season: NoLimit, WaiverDays: DefaultWaiverDays}
+}
+
+// Function to check that every limit is either set or NoLimit
+func (al AcquisitionLimits) Validate() error {
+	if al.PerWeek < 0 {
+		return fmt.Errorf("max acquisitions per week can't be negative")
+	}
+	if al.PerDay < NoLimit {
+		return fmt.Errorf("max acquisitions per day can't be negative")
+	}
+	if al.Season < NoLimit {
+		return fmt.Errorf("season acquisitions left can't be negative")
+	}
+	if al.WaiverDays < 0 {
+		return fmt.Errorf("waiver days can't be negative")
+	}
+	if al.UsedThisWeek < 0 {
+		return fmt.Errorf("acquisitions used can't be negative")
+	}
+	return nil
+}
+
+// Function to get the most acquisitions that can still be made this week, the season budget caps the weekly limit
+func (al AcquisitionLimits) WeeklyLimit() int {
+	per_week := max(al.PerWeek-al.UsedThisWeek, 0)
+	if al.Season == NoLimit {
+		return per_week
+	}
+	return min(per_week, al.Season)
+}
+
+// Function to get how many more acquisitions can be made on a day given the ones already made that week and day
+func (al AcquisitionLimits) Available(week_count int, day_count int) int {
+	available := al.WeeklyLimit() - week_count
+	if al.PerDay != NoLimit {
+		available = min(available, al.PerDay-day_count)
+	}
+	return max(available, 0)
+}
+
+// Function to check that the acquisitions made on each day of a plan stay within the limits
+func (al AcquisitionLimits) Allows(daily_counts []int) bool {
+	total := 0
+	for _, count := range daily_counts {
+		if al.PerDay != NoLimit && count > al.PerDay {
+			return false
+		}
+		total += count
+	}
+	return total <= al.WeeklyLimit()
+}
@@ -0,0 +1,90 @@
+// Copy of v3/helpers/injury.go, only the package name differs. Each version is its own module, so the copies are
+// kept in sync by TestSharedFilesInSync in v3/tests/shared_files_test.go
+
+package data
+
+import "fmt"
+
+// Where a player stands on the league's injury report, which decides how likely he is to play
+type InjuryStatus string
+
+const (
+	// Not on the injury report
+	Healthy InjuryStatus = ""
+	// Day-to-day, usually plays through it
+	InjuryDayToDay InjuryStatus = "DTD"
+	// Game-time decision
+	InjuryQuestionable InjuryStatus = "QUESTIONABLE"
+	// Unlikely to play
+	InjuryDoubtful InjuryStatus = "DOUBTFUL"
+	// Won't play until he returns
+	InjuryOut InjuryStatus = "OUT"
+	// On injured reserve, won't play until he returns
+	InjuryReserve InjuryStatus = "IR"
+)
+
+// Function to check that an injury status is known
+func (s InjuryStatus) Validate() error {
+	switch s {
+	case Healthy, InjuryDayToDay, InjuryQuestionable, InjuryDoubtful, InjuryOut, InjuryReserve:
+		return nil
+	default:
+		return fmt.Errorf("unknown injury status %q", string(s))
+	}
+}
+
+// Function to get the chance a player with this status plays a game
+func (s InjuryStatus) PlayProbability() float64 {
+	switch s {
+	case InjuryDayToDay:
+		return 0.75
+	case InjuryQuestionable:
+		return 0.5
+	case InjuryDoubtful:
+		return 0.25
+	case InjuryOut, InjuryReserve:
+		return 0.0
+	default:
+		return 1.0
+	}
+}
+
+// Function that returns a player's injury status, a player who is only marked as injured is out
+func (p Player) GetInjuryStatus() InjuryStatus {
+	if p.InjuryStatus == Healthy && p.Injured {
+		return InjuryOut
+	}
+	return p.InjuryStatus
+}
+
+// Function that returns the chance a player plays his team's game on [day]. His status holds until his expected
+// return day and he's healthy from then on, without a return day it holds for the whole plan
+func (p Player) PlayProbability(day int) float64 {
+	if p.ReturnDay != nil && day >= *p.ReturnDay {
+		return 1.0
+	}
+	return p.GetInjuryStatus().PlayProbability()
+}
+
+// Function that returns whether a player can't play on any day from [start] up to [end]
+func (p Player) IsOutBetween(start int, end int) bool {
+	for day := start; day < end; day++ {
+		if p.PlayProbability(day) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Function to check the injury statuses and return days of the players in a request
+func ValidateInjuries(players []Player) error {
+	for _, player := range players {
+		if err := player.InjuryStatus.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", player.Name, err)
+		}
+		if player.ReturnDay != nil && *player.ReturnDay < 0 {
+			return fmt.Errorf("%s: return day must not be negative, got %d", player.Name, *player.ReturnDay)
+		}
+	}
+	return nil
+}
@@ -1,5 +1,6 @@
 package data
 
+import "strconv"
 
 // Struct for how to contruct Players using the returned player data
 type Player struct {
@@ -8,6 +9,14 @@ type Player struct {
 	Team           string   `json:"team"`
 	ValidPositions []string `json:"valid_positions"`
 	Injured        bool     `json:"injured"`
+	WaiverUntilDay int      `json:"waiver_until_day"`
+
+	// Injury report status and the day of the plan the player is expected back, an injured player without a status is out
+	InjuryStatus InjuryStatus `json:"injury_status"`
+	ReturnDay    *int         `json:"return_day"`
+
+	// Expected points per game keyed by day of the plan or by date like 2025-10-21, AvgPoints is used for games without one
+	Projections map[string]float64 `json:"projections"`
 }
 
 // Functions that return the player's fields
@@ -42,8 +51,33 @@ func (p *Player) PlaysPosition(position string) bool {
 }
 
 
+// Function that returns whether a player is still on waivers on a day of the week. Players clear waivers on
+// [WaiverUntilDay] and can be picked up from then on
+func (p Player) IsOnWaivers(day int) bool {
+	return day < p.WaiverUntilDay
+}
+
+// Function that returns a player's projected points for a game on [day] or [date], a projection for the day comes first
+func (p Player) GetProjection(day int, date string) (float64, bool) {
+	if points, ok := p.Projections[strconv.Itoa(day)]; ok {
+		return points, true
+	}
+	if points, ok := p.Projections[date]; ok && date != "" {
+		return points, true
+	}
+	return 0, false
+}
+
+// Function that returns a player's expected points for a game on [day] or [date], his average when there's no projection
+func (p Player) GetProjectedPoints(day int, date string) float64 {
+	if points, ok := p.GetProjection(day, date); ok {
+		return points
+	}
+	return p.AvgPoints
+}
+
 // Struct for organizing data on a player who has been dropped
 type DroppedPlayer struct {
 	Player 	  Player
-	Countdown int
+	Countdown int // Days left before the player clears waivers
 }
\ No newline at end of file
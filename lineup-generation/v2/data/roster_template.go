@@ -0,0 +1,202 @@
+// Copy of v3/helpers/roster_template.go, only the package name differs. Each version is its own module, so the copies are
+// kept in sync by TestSharedFilesInSync in v3/tests/shared_files_test.go
+
+package data
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// Restrictiveness weight of a bench slot, lower than any starting slot
+const BenchWeight = 1
+
+// Struct for a single starting slot in a league's roster and the positions that can fill it.
+// Weight is optional, when it is 0 the weight is derived from how many positions the slot accepts
+type Slot struct {
+	Name      string   `json:"name"`
+	Positions []string `json:"positions"`
+	Weight    int      `json:"weight"`
+}
+
+// Struct for a league's roster layout. Starting slots are listed from most to least restrictive
+type RosterTemplate struct {
+	Slots []Slot `json:"slots"`
+	Bench int    `json:"bench"`
+	IR    int    `json:"ir"`
+}
+
+// Function to get the standard ESPN roster layout
+func DefaultRosterTemplate() RosterTemplate {
+	return RosterTemplate{
+		Slots: []Slot{
+			{Name: "PG", Positions: []string{"PG"}},
+			{Name: "SG", Positions: []string{"SG"}},
+			{Name: "SF", Positions: []string{"SF"}},
+			{Name: "PF", Positions: []string{"PF"}},
+			{Name: "G", Positions: []string{"PG", "SG", "G"}},
+			{Name: "F", Positions: []string{"SF", "PF", "F"}},
+			{Name: "C", Positions: []string{"C"}},
+			{Name: "UT1", Positions: []string{"PG", "SG", "SF", "PF", "G", "F", "C", "UT1"}},
+			{Name: "UT2", Positions: []string{"PG", "SG", "SF", "PF", "G", "F", "C", "UT2"}},
+			{Name: "UT3", Positions: []string{"PG", "SG", "SF", "PF", "G", "F", "C", "UT3"}},
+		},
+		Bench: 3,
+		IR:    1,
+	}
+}
+
+// Function to check that a template can be used to slot players
+func (rt *RosterTemplate) Validate() error {
+	if len(rt.Slots) == 0 {
+		return fmt.Errorf("roster template has no starting slots")
+	}
+	if rt.Bench < 0 || rt.IR < 0 {
+		return fmt.Errorf("roster template bench and IR counts can't be negative")
+	}
+
+	names := make(map[string]bool)
+	for _, name := range rt.BenchSlots() {
+		names[name] = true
+	}
+	for _, slot := range rt.Slots {
+		if slot.Name == "" {
+			return fmt.Errorf("roster template has a slot with no name")
+		}
+		if names[slot.Name] {
+			return fmt.Errorf("roster template slot %s is listed more than once or clashes with a bench slot", slot.Name)
+		}
+		if len(slot.Positions) == 0 {
+			return fmt.Errorf("roster template slot %s doesn't accept any positions", slot.Name)
+		}
+		names[slot.Name] = true
+	}
+
+	return nil
+}
+
+// Function to get the names of the starting slots from most to least restrictive
+func (rt *RosterTemplate) StartingSlots() []string {
+	slots := make([]string, len(rt.Slots))
+	for i, slot := range rt.Slots {
+		slots[i] = slot.Name
+	}
+	return slots
+}
+
+// Function to get the names of the bench slots (BE1, BE2, ...)
+func (rt *RosterTemplate) BenchSlots() []string {
+	slots := make([]string, rt.Bench)
+	for i := range slots {
+		slots[i] = "BE" + strconv.Itoa(i+1)
+	}
+	return slots
+}
+
+// Function to get every slot a playing player can go in, from most to least restrictive
+func (rt *RosterTemplate) SlotOrder() []string {
+	return append(rt.StartingSlots(), rt.BenchSlots()...)
+}
+
+// Function to check if a slot is a bench slot
+func (rt *RosterTemplate) IsBench(name string) bool {
+	for _, slot := range rt.BenchSlots() {
+		if slot == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Function to check if a player can fill a slot. Bench slots take anyone
+func (rt *RosterTemplate) Accepts(name string, player Player) bool {
+	for _, slot := range rt.Slots {
+		if slot.Name == name {
+			for _, position := range slot.Positions {
+				if player.PlaysPosition(position) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	if rt.IsBench(name) {
+		return true
+	}
+
+	// Unknown slots fall back to the player's listed positions
+	return player.PlaysPosition(name)
+}
+
+// Function to get the restrictiveness weight of a slot
+func (rt *RosterTemplate) Weight(name string) int {
+	return rt.Weights()[name]
+}
+
+// Function to get the restrictiveness weight of every slot. Slots without an explicit weight are ranked
+// by how many positions they accept, so the narrowest slots weigh the most and every starting slot
+// weighs more than the bench
+func (rt *RosterTemplate) Weights() map[string]int {
+
+	// Distinct number of accepted positions from the broadest slot to the narrowest
+	counts := make([]int, 0, len(rt.Slots))
+	for _, slot := range rt.Slots {
+		if slot.Weight > 0 {
+			continue
+		}
+		if count := slot.NumPositions(); !slices.Contains(counts, count) {
+			counts = append(counts, count)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+
+	weights := make(map[string]int, len(rt.Slots)+rt.Bench)
+	for _, slot := range rt.Slots {
+		if slot.Weight > 0 {
+			weights[slot.Name] = slot.Weight
+			continue
+		}
+		for rank, count := range counts {
+			if count == slot.NumPositions() {
+				weights[slot.Name] = BenchWeight + 1 + rank
+				break
+			}
+		}
+	}
+	for _, name := range rt.BenchSlots() {
+		weights[name] = BenchWeight
+	}
+
+	return weights
+}
+
+// Function to get the number of distinct positions a slot accepts
+func (s Slot) NumPositions() int {
+	seen := make(map[string]bool, len(s.Positions))
+	for _, position := range s.Positions {
+		seen[position] = true
+	}
+	return len(seen)
+}
+
+// Objective used when slotting the players that aren't streamed
+type SlottingMode string
+
+const (
+	// Fill the most restrictive slots first, points only break ties
+	SlotByRestrictiveness SlottingMode = "restrictiveness"
+	// Start the highest scoring players, restrictiveness only breaks ties
+	SlotByPoints SlottingMode = "points"
+)
+
+// Function to check that a slotting mode is known
+func (m SlottingMode) Validate() error {
+	switch m {
+	case SlotByRestrictiveness, SlotByPoints:
+		return nil
+	default:
+		return fmt.Errorf("unknown slotting mode %q", string(m))
+	}
+}
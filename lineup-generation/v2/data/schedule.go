@@ -1,19 +1,24 @@
 package data
 
 import (
-	"encoding/json"
 	"strconv"
 	"fmt"
-	"io"
 	"os"
+	"time"
 )
 
+// Layout of the dates in requests and responses
+const ISODateLayout = "2006-01-02"
+
 // Struct for JSON schedule file that is used to get days a player is playing
 type WeekSchedule struct {
 	StartDate     string           	   	  	 `json:"startDate"`
 	EndDate       string           	      	 `json:"endDate"`
 	GameSpan  	  int                     	 `json:"gameSpan"`
 	TeamSchedules map[string]map[string]bool `json:"games"`
+
+	// Opponent, home/away and tip-off for each game, only filled from versioned schedule files
+	Games map[string][]Game `json:"-"`
 }
 
 // Struct to organize the season schedule
@@ -21,37 +26,50 @@ type SeasonSchedule struct {
 	Schedule map[string]WeekSchedule `json:"schedule"`
 }
 
-var ScheduleMap SeasonSchedule
+// Options for loading a schedule file
+type LoadOptions struct {
+	// Refuse the schedule if ValidateSchedule finds problems instead of planning with it
+	RejectInvalid bool
 
-func InitSchedule(path string) {
-	LoadSchedule(path)
+	// Only problems in these weeks refuse the schedule, every week counts when empty
+	Weeks []int
 }
 
-// Function to load schedule from JSON file into memory
-func LoadSchedule(path string) {
-	if ScheduleMap.Schedule != nil { // If the schedule has already been loaded, don't load it again
-		fmt.Println("Schedule already loaded")
-		return
-	}
-	
-	// Load JSON schedule file
-	json_schedule, err := os.Open(path)
+// Function to load a schedule file, printing the problem and returning an empty schedule when it can't be loaded
+func InitSchedule(path string) SeasonSchedule {
+	schedule, err := LoadSchedule(path)
 	if err != nil {
-		fmt.Println("Error opening json schedule:", err)
+		fmt.Println("Error loading schedule:", err)
 	}
-	defer json_schedule.Close()
+	return schedule
+}
+
+// Function to load schedule from JSON file
+func LoadSchedule(path string) (SeasonSchedule, error) {
+	return LoadScheduleWithOptions(path, LoadOptions{})
+}
 
-	// Read the contents of the json_schedule file
-	jsonBytes, err := io.ReadAll(json_schedule)
+// Function to load schedule from JSON file, refusing it if it has problems in the given weeks when asked to
+func LoadScheduleWithOptions(path string, options LoadOptions) (SeasonSchedule, error) {
+	// Load JSON schedule file
+	jsonBytes, err := os.ReadFile(path)
 	if err != nil {
-		fmt.Println("Error reading json_schedule:", err)
+		return SeasonSchedule{}, err
 	}
 
-	// Unmarshal the JSON data into ScheduleMap
-	err = json.Unmarshal(jsonBytes, &ScheduleMap)
-	if err != nil {
-		fmt.Println("Error turning jsonBytes into map:", err)
+	// A schedule with problems would quietly plan with missing or misplaced games
+	if options.RejectInvalid {
+		errs := ValidateSchedule(jsonBytes)
+		if len(options.Weeks) > 0 {
+			errs = errs.ForWeeks(options.Weeks...)
+		}
+		if err := errs.Err(); err != nil {
+			return SeasonSchedule{}, err
+		}
 	}
+
+	// Parse the JSON data, versioned and legacy files are both understood
+	return ParseSeasonSchedule(jsonBytes)
 }
 
 // Function to get the schedule for a specific week
@@ -73,6 +91,111 @@ func (s *SeasonSchedule) IsPlaying(week int, day int, team string) bool {
 	}
 }
 
+// Function to count the games a team plays in a specific week
+func (s *SeasonSchedule) CountGames(week int, team string) int {
+	return len(s.Schedule[strconv.Itoa(week)].TeamSchedules[team])
+}
+
+// Function to get a player's expected points for a game on a day of a specific week, weighted by his chance of playing.
+// The date is only needed for projections
+func (s *SeasonSchedule) GetPoints(player Player, week int, day int) float64 {
+	points := player.AvgPoints
+	if len(player.Projections) > 0 {
+		points = player.GetProjectedPoints(day, s.GetISODate(week, day))
+	}
+	return points * player.PlayProbability(day)
+}
+
+// Function to check if a player is expected to sit out his team's game on a day of a specific week, like the second
+// night of a back-to-back or a game he's injured for
+func (s *SeasonSchedule) IsResting(player Player, week int, day int) bool {
+	if player.PlayProbability(day) == 0 {
+		return true
+	}
+	if len(player.Projections) == 0 {
+		return false
+	}
+	points, ok := player.GetProjection(day, s.GetISODate(week, day))
+	return ok && points <= 0
+}
+
+// Function to get a player's expected points over every game his team plays in a specific week
+func (s *SeasonSchedule) WeeklyPoints(player Player, week int) float64 {
+	total := 0.0
+	for day := range s.GetGameSpan(week) {
+		if s.IsPlaying(week, day, player.Team) {
+			total += s.GetPoints(player, week, day)
+		}
+	}
+	return total
+}
+
+// Function to get the details of a team's games in a specific week, empty when the schedule file has none
+func (s *SeasonSchedule) GetGames(week int, team string) []Game {
+	return s.Schedule[strconv.Itoa(week)].Games[team]
+}
+
+// Function to get the calendar date of a day in a specific week, the zero time when the week isn't in the schedule
+func (s *SeasonSchedule) GetDate(week int, day int) time.Time {
+	week_schedule := s.GetWeekSchedule(week)
+	return week_schedule.GetDate(day)
+}
+
+// Function to get the date of a day in a specific week in the response format, empty when the week isn't in the schedule
+func (s *SeasonSchedule) GetISODate(week int, day int) string {
+	date := s.GetDate(week, day)
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(ISODateLayout)
+}
+
+// Function to find the week and day that [date] falls on
+func (s *SeasonSchedule) FindWeek(date time.Time) (int, int, error) {
+	for key, week_schedule := range s.Schedule {
+		day, ok := week_schedule.GetDay(date)
+		if !ok {
+			continue
+		}
+		week, err := strconv.Atoi(key)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid week %q in schedule", key)
+		}
+		return week, day, nil
+	}
+	return 0, 0, fmt.Errorf("no week in the schedule contains %s", date.Format(ISODateLayout))
+}
+
+// Function to parse a date from a request, like 2025-10-21
+func ParseISODate(value string) (time.Time, error) {
+	return time.Parse(ISODateLayout, value)
+}
+
+// Function to get the calendar date the week starts on
+func (w *WeekSchedule) GetStartTime() (time.Time, error) {
+	return time.Parse(WeekDateLayout, w.StartDate)
+}
+
+// Function to get the calendar date of [day], the zero time when the week has no valid start date
+func (w *WeekSchedule) GetDate(day int) time.Time {
+	start, err := w.GetStartTime()
+	if err != nil {
+		return time.Time{}
+	}
+	return start.AddDate(0, 0, day)
+}
+
+// Function to get the day index of [date] in the week, false when the date is outside the week
+func (w *WeekSchedule) GetDay(date time.Time) (int, bool) {
+	start, err := w.GetStartTime()
+	if err != nil {
+		return 0, false
+	}
+	calendar_date := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	day := int(calendar_date.Sub(start).Hours() / 24)
+	return day, !calendar_date.Before(start) && day < w.GameSpan
+}
+
 func (w *WeekSchedule) GetStartDate() string {
 	return w.StartDate
 }
@@ -0,0 +1,111 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Version of the schedule file format with per game details. Files without a version are one of the legacy layouts
+const ScheduleVersion = 1
+
+// Versioned schedule file shared by every version of the lineup generator, v3/helpers/schedule_file.go reads the same
+// format into week schedules
+type ScheduleFile struct {
+	Version int                     `json:"version"`
+	Season  string                  `json:"season,omitempty"`
+	Weeks   map[string]ScheduleWeek `json:"weeks"`
+}
+
+// One week of the versioned schedule file, games are listed per team
+type ScheduleWeek struct {
+	StartDate string            `json:"startDate"`
+	EndDate   string            `json:"endDate"`
+	GameSpan  int               `json:"gameSpan"`
+	Games     map[string][]Game `json:"games"`
+}
+
+// Single game from one team's side. Legacy files only know the day, so the other details can be empty and [Home] is nil
+// when it isn't known whether the team plays at home
+type Game struct {
+	Day      int    `json:"day"`
+	Opponent string `json:"opponent,omitempty"`
+	Home     *bool  `json:"home,omitempty"`
+	TipOff   string `json:"tipoff,omitempty"`
+}
+
+// Function to parse a schedule file in the versioned format or either legacy layout into a season schedule
+func ParseSeasonSchedule(data []byte) (SeasonSchedule, error) {
+
+	var header struct {
+		Version  *int            `json:"version"`
+		Schedule json.RawMessage `json:"schedule"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return SeasonSchedule{}, err
+	}
+
+	switch {
+	case header.Version != nil:
+		if *header.Version != ScheduleVersion {
+			return SeasonSchedule{}, fmt.Errorf("unsupported schedule version %d", *header.Version)
+		}
+		var file ScheduleFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return SeasonSchedule{}, err
+		}
+
+		season := SeasonSchedule{Schedule: make(map[string]WeekSchedule, len(file.Weeks))}
+		for key, week := range file.Weeks {
+			schedule := WeekSchedule{
+				StartDate:     week.StartDate,
+				EndDate:       week.EndDate,
+				GameSpan:      week.GameSpan,
+				TeamSchedules: make(map[string]map[string]bool, len(week.Games)),
+				Games:         make(map[string][]Game, len(week.Games)),
+			}
+			for team, games := range week.Games {
+				schedule.TeamSchedules[team] = make(map[string]bool, len(games))
+				for _, game := range games {
+					schedule.TeamSchedules[team][strconv.Itoa(game.Day)] = true
+				}
+				schedule.Games[team] = append([]Game{}, games...)
+			}
+			season.Schedule[key] = schedule
+		}
+		return season, nil
+
+	case header.Schedule != nil:
+		// Legacy v2 layout, the weeks are under a top level "schedule" key
+		var season SeasonSchedule
+		if err := json.Unmarshal(data, &season); err != nil {
+			return SeasonSchedule{}, err
+		}
+		return season, nil
+
+	default:
+		// Legacy v3 layout, weeks at the top level with a list of days per team
+		var legacy map[string]struct {
+			StartDate     string           `json:"startDate"`
+			EndDate       string           `json:"endDate"`
+			GameSpan      int              `json:"gameSpan"`
+			TeamSchedules map[string][]int `json:"games"`
+		}
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return SeasonSchedule{}, err
+		}
+
+		season := SeasonSchedule{Schedule: make(map[string]WeekSchedule, len(legacy))}
+		for key, week := range legacy {
+			team_schedules := make(map[string]map[string]bool, len(week.TeamSchedules))
+			for team, days := range week.TeamSchedules {
+				team_schedules[team] = make(map[string]bool, len(days))
+				for _, day := range days {
+					team_schedules[team][strconv.Itoa(day)] = true
+				}
+			}
+			season.Schedule[key] = WeekSchedule{StartDate: week.StartDate, EndDate: week.EndDate, GameSpan: week.GameSpan, TeamSchedules: team_schedules}
+		}
+		return season, nil
+	}
+}
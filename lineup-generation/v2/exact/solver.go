@@ -0,0 +1,489 @@
+package exact
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	d "v2/data"
+	t "v2/team"
+	u "v2/utils"
+)
+
+// Default number of search nodes before the solver gives up and returns the best plan found so far
+const DefaultNodeLimit = 2000000
+
+// Struct for a single add/drop transaction, players are referenced by their index in Solver.players
+type Move struct {
+	Drop int
+	Add  int
+}
+
+// Struct for the result of the exact solver
+type Result struct {
+	Moves        [][]Move
+	Score        float64
+	BaseScore    float64
+	UpperBound   float64
+	Gap          float64
+	Optimal      bool
+	Nodes        int
+	Acquisitions int
+}
+
+// Struct for the branch and bound search over add/drop decisions
+type Solver struct {
+	bt               *t.BaseTeam
+	game_span        int
+	max_acquisitions int
+	max_per_day      int
+	node_limit       int
+
+	// Streamable players on the roster followed by the free agents that can be picked up
+	players []d.Player
+	plays   [][]bool
+
+	// Points a player is expected to score in his game on each day
+	points [][]float64
+
+	// Points a player can score from a day until the end of the week
+	remaining_points [][]float64
+
+	// Cache of the best daily assignment for a set of players
+	slot_cache map[string]float64
+
+	// Search state
+	roster      []int
+	moves       [][]Move
+	dropped_day map[int]int
+	value       float64
+	nodes       int
+	aborted     bool
+	open_bound  float64
+	best_score  float64
+	best_moves  [][]Move
+}
+
+// Function to create a new solver for a base team, the per day cap comes from the team's acquisition limits
+func InitSolver(bt *t.BaseTeam, max_acquisitions int, node_limit int) *Solver {
+
+	s := &Solver{
+		bt:               bt,
+		game_span:        bt.Schedule.GetGameSpan(bt.Week),
+		max_acquisitions: max_acquisitions,
+		max_per_day:      bt.Limits.PerDay,
+		node_limit:       node_limit,
+		players:          make([]d.Player, 0, len(bt.StreamablePlayers)+len(bt.FreeAgents)),
+		slot_cache:       make(map[string]float64),
+		dropped_day:      make(map[int]int),
+	}
+
+	// The current streamers make up the starting roster
+	seen := make(map[string]bool)
+	for _, player := range bt.StreamablePlayers {
+		s.roster = append(s.roster, len(s.players))
+		s.players = append(s.players, player)
+		seen[player.Name] = true
+	}
+
+	// Free agents who are out all week and players already on the roster can't be picked up
+	for _, player := range bt.FreeAgents {
+		if player.IsOutBetween(0, s.game_span) || seen[player.Name] {
+			continue
+		}
+		if _, ok := bt.RosterMap[player.Name]; ok {
+			continue
+		}
+		s.players = append(s.players, player)
+		seen[player.Name] = true
+	}
+
+	// Precompute which days each player plays and how many points they can score from each day on. A player projected
+	// to rest doesn't play that day
+	s.plays = make([][]bool, len(s.players))
+	s.points = make([][]float64, len(s.players))
+	s.remaining_points = make([][]float64, len(s.players))
+	for i, player := range s.players {
+		s.plays[i] = make([]bool, s.game_span)
+		s.points[i] = make([]float64, s.game_span)
+		s.remaining_points[i] = make([]float64, s.game_span+1)
+		for day := s.game_span - 1; day >= 0; day-- {
+			s.plays[i][day] = bt.Schedule.IsPlaying(bt.Week, day, player.Team) && !bt.Schedule.IsResting(player, bt.Week, day)
+			s.remaining_points[i][day] = s.remaining_points[i][day+1]
+			if s.plays[i][day] {
+				s.points[i][day] = bt.Schedule.GetPoints(player, bt.Week, day)
+				s.remaining_points[i][day] += s.points[i][day]
+			}
+		}
+	}
+
+	s.moves = make([][]Move, s.game_span)
+	for day := range s.moves {
+		s.moves[day] = make([]Move, 0)
+	}
+
+	return s
+}
+
+// Function to solve for the plan that maximizes the points scored by streamers over the week
+func (s *Solver) Solve() Result {
+
+	// The plan with no moves is the first incumbent, the days before the current day are already over
+	base_score := 0.0
+	for day := s.bt.CurrentDay; day < s.game_span; day++ {
+		base_score += s.SlotValue(s.roster, day)
+	}
+	s.best_score = base_score
+	s.best_moves = s.CopyMoves()
+
+	s.Search(s.bt.CurrentDay, 0, 0)
+
+	upper_bound := s.best_score
+	if s.aborted && s.open_bound > upper_bound {
+		upper_bound = s.open_bound
+	}
+
+	gap := 0.0
+	if upper_bound > 0 {
+		gap = (upper_bound - s.best_score) / upper_bound
+	}
+
+	acquisitions := 0
+	for _, day_moves := range s.best_moves {
+		acquisitions += len(day_moves)
+	}
+
+	return Result{
+		Moves:        s.best_moves,
+		Score:        s.best_score,
+		BaseScore:    base_score,
+		UpperBound:   upper_bound,
+		Gap:          gap,
+		Optimal:      !s.aborted,
+		Nodes:        s.nodes,
+		Acquisitions: acquisitions,
+	}
+}
+
+// Recursive branch and bound over the moves made on each day, [min_add] keeps the moves on a day in a canonical order
+func (s *Solver) Search(day int, acquisitions int, min_add int) {
+
+	if s.aborted {
+		return
+	}
+	s.nodes++
+	if s.nodes > s.node_limit {
+		s.aborted = true
+	}
+
+	// Every day has been decided, check if the plan is better than the incumbent
+	if day == s.game_span {
+		if s.value > s.best_score {
+			s.best_score = s.value
+			s.best_moves = s.CopyMoves()
+		}
+		return
+	}
+
+	// Prune if even the optimistic bound can't beat the incumbent
+	bound := s.UpperBound(day, acquisitions)
+	if bound <= s.best_score {
+		return
+	}
+	if s.aborted {
+		s.open_bound = max(s.open_bound, bound)
+		return
+	}
+
+	// Children are either one more move today or closing out the day, explored in order of remaining week value
+	type child struct {
+		move  Move
+		stay  bool
+		value float64
+	}
+	children := []child{{stay: true, value: s.RemainingValue(s.roster, day)}}
+
+	if acquisitions < s.max_acquisitions && (s.max_per_day == d.NoLimit || len(s.moves[day]) < s.max_per_day) {
+		for add := min_add; add < len(s.players); add++ {
+			if !s.IsAvailable(add, day) {
+				continue
+			}
+			for i, drop := range s.roster {
+
+				// Don't drop a player that was picked up on the same day
+				if s.WasAddedOn(drop, day) {
+					continue
+				}
+				s.roster[i] = add
+				children = append(children, child{move: Move{Drop: drop, Add: add}, value: s.RemainingValue(s.roster, day)})
+				s.roster[i] = drop
+			}
+		}
+	}
+
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].value > children[j].value
+	})
+
+	for _, c := range children {
+		if c.stay {
+			day_value := s.SlotValue(s.roster, day)
+			s.value += day_value
+			s.Search(day+1, acquisitions, 0)
+			s.value -= day_value
+		} else {
+			prev_day, was_dropped := s.dropped_day[c.move.Drop]
+			s.ApplyMove(day, c.move)
+			s.Search(day, acquisitions+1, c.move.Add+1)
+			s.UndoMove(day, c.move)
+			if was_dropped {
+				s.dropped_day[c.move.Drop] = prev_day
+			}
+		}
+
+		if s.aborted {
+			s.open_bound = max(s.open_bound, bound)
+			return
+		}
+	}
+}
+
+// Function to get an upper bound on the final score from the current node
+func (s *Solver) UpperBound(day int, acquisitions int) float64 {
+
+	// Points the current roster can score for the rest of the week
+	bound := s.value + s.RemainingValue(s.roster, day)
+
+	// Each acquisition adds at most all the points the new player can score for the rest of the week
+	acq_left := s.max_acquisitions - acquisitions
+	if acq_left <= 0 {
+		return bound
+	}
+
+	gains := make([]float64, 0, len(s.players))
+	for i := range s.players {
+		if !s.IsRostered(i) && s.remaining_points[i][day] > 0 {
+			gains = append(gains, s.remaining_points[i][day])
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(gains)))
+	for i := 0; i < acq_left && i < len(gains); i++ {
+		bound += gains[i]
+	}
+
+	return bound
+}
+
+// Function to get the points a roster scores from [day] until the end of the week
+func (s *Solver) RemainingValue(roster []int, day int) float64 {
+	value := 0.0
+	for i := day; i < s.game_span; i++ {
+		value += s.SlotValue(roster, i)
+	}
+	return value
+}
+
+// Function to get the best points total from slotting a roster's playing players into the unused positions on a day
+func (s *Solver) SlotValue(roster []int, day int) float64 {
+
+	playing := make([]int, 0, len(roster))
+	for _, player := range roster {
+		if s.plays[player][day] {
+			playing = append(playing, player)
+		}
+	}
+	sort.Ints(playing)
+
+	key := s.CacheKey(day, playing)
+	if value, ok := s.slot_cache[key]; ok {
+		return value
+	}
+
+	value, _ := s.AssignSlots(playing, day)
+	s.slot_cache[key] = value
+
+	return value
+}
+
+// Function to find the max points assignment of players to the unused positions on a day
+func (s *Solver) AssignSlots(playing []int, day int) (float64, map[string]int) {
+
+	positions := make([]string, 0, len(s.bt.UnusedPositions[day]))
+	for pos, open := range s.bt.UnusedPositions[day] {
+		if open {
+			positions = append(positions, pos)
+		}
+	}
+	sort.Strings(positions)
+
+	best_value := 0.0
+	best_assignment := make(map[string]int)
+	assignment := make(map[string]int)
+
+	// Recursive search where each player either takes an open position he plays or sits on the bench
+	var assign func(index int, value float64)
+	assign = func(index int, value float64) {
+		if index == len(playing) {
+			if value > best_value {
+				best_value = value
+				best_assignment = make(map[string]int, len(assignment))
+				for pos, player := range assignment {
+					best_assignment[pos] = player
+				}
+			}
+			return
+		}
+
+		player := s.players[playing[index]]
+		for _, pos := range positions {
+			if _, taken := assignment[pos]; taken || !s.bt.Template.Accepts(pos, player) {
+				continue
+			}
+			assignment[pos] = playing[index]
+			assign(index+1, value+s.points[playing[index]][day])
+			delete(assignment, pos)
+		}
+		assign(index+1, value)
+	}
+	assign(0, 0.0)
+
+	return best_value, best_assignment
+}
+
+// Function to check if a player can be picked up on a day
+func (s *Solver) IsAvailable(player int, day int) bool {
+
+	// Only pick up players on days they play, picking them up later never loses points
+	if !s.plays[player][day] || s.IsRostered(player) {
+		return false
+	}
+
+	// Free agents on waivers and dropped players have to clear waivers first
+	if s.players[player].IsOnWaivers(day) {
+		return false
+	}
+	if dropped_day, ok := s.dropped_day[player]; ok && day < dropped_day+s.bt.Limits.WaiverDays {
+		return false
+	}
+
+	return true
+}
+
+// Function to check if a player is on the current roster
+func (s *Solver) IsRostered(player int) bool {
+	for _, p := range s.roster {
+		if p == player {
+			return true
+		}
+	}
+	return false
+}
+
+// Function to check if a player was picked up on a day
+func (s *Solver) WasAddedOn(player int, day int) bool {
+	for _, move := range s.moves[day] {
+		if move.Add == player {
+			return true
+		}
+	}
+	return false
+}
+
+// Function to apply a move to the current roster
+func (s *Solver) ApplyMove(day int, move Move) {
+	for i, player := range s.roster {
+		if player == move.Drop {
+			s.roster[i] = move.Add
+			break
+		}
+	}
+	s.moves[day] = append(s.moves[day], move)
+	s.dropped_day[move.Drop] = day
+}
+
+// Function to undo the last move made on a day
+func (s *Solver) UndoMove(day int, move Move) {
+	for i, player := range s.roster {
+		if player == move.Add {
+			s.roster[i] = move.Drop
+			break
+		}
+	}
+	s.moves[day] = s.moves[day][:len(s.moves[day])-1]
+	delete(s.dropped_day, move.Drop)
+}
+
+// Function to copy the moves made so far
+func (s *Solver) CopyMoves() [][]Move {
+	moves := make([][]Move, len(s.moves))
+	for day, day_moves := range s.moves {
+		moves[day] = append([]Move{}, day_moves...)
+	}
+	return moves
+}
+
+// Function to build the cache key for a day and a sorted set of players
+func (s *Solver) CacheKey(day int, players []int) string {
+	parts := make([]string, len(players)+1)
+	parts[0] = strconv.Itoa(day)
+	for i, player := range players {
+		parts[i+1] = strconv.Itoa(player)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Function to convert the moves in a result into the genes returned by the API
+func (s *Solver) Slim(result Result) []u.SlimGene {
+
+	slim := func(player d.Player) u.SlimPlayer {
+		return u.SlimPlayer{Name: player.Name, AvgPoints: player.AvgPoints, Team: player.Team}
+	}
+
+	roster := make([]int, 0, len(s.bt.StreamablePlayers))
+	for i := range s.bt.StreamablePlayers {
+		roster = append(roster, i)
+	}
+
+	genes := make([]u.SlimGene, s.game_span)
+	for day := 0; day < s.game_span; day++ {
+		gene := u.SlimGene{
+			Day:       day,
+			Additions: make([]u.SlimPlayer, 0, len(result.Moves[day])),
+			Removals:  make([]u.SlimPlayer, 0, len(result.Moves[day])),
+			Roster:    make(map[string]u.SlimPlayer),
+		}
+
+		// Apply the day's moves to the roster
+		for _, move := range result.Moves[day] {
+			for i, player := range roster {
+				if player == move.Drop {
+					roster[i] = move.Add
+					break
+				}
+			}
+			gene.Additions = append(gene.Additions, slim(s.players[move.Add]))
+			gene.Removals = append(gene.Removals, slim(s.players[move.Drop]))
+		}
+
+		// Add back the non-streamable players and slot the streamers that are playing
+		for pos, player := range s.bt.OptimalSlotting[day] {
+			if player.Name != "" {
+				gene.Roster[pos] = slim(player)
+			}
+		}
+		playing := make([]int, 0, len(roster))
+		for _, player := range roster {
+			if s.plays[player][day] {
+				playing = append(playing, player)
+			}
+		}
+		sort.Ints(playing)
+		_, assignment := s.AssignSlots(playing, day)
+		for pos, player := range assignment {
+			gene.Roster[pos] = slim(s.players[player])
+		}
+
+		genes[day] = gene
+	}
+
+	return genes
+}
@@ -2,7 +2,8 @@ package population
 
 import (
 	"fmt"
-	"math"
+	"maps"
+	"slices"
 	"sort"
 	"math/rand"
 	d "v2/data"
@@ -19,26 +20,34 @@ type Chromosome struct {
 	DroppedPlayers    map[string]d.DroppedPlayer
 	CurStreamers      []d.Player
 	Week              int
+	Template          d.RosterTemplate
+	Limits            d.AcquisitionLimits
+	CurrentDay        int
+	Schedule          *d.SeasonSchedule
 }
 
 // Function to create a new chromosome
 func InitChromosome(bt *t.BaseTeam) *Chromosome {
 	
 	// Create a new chromosome
-	chromosome := &Chromosome{Genes: make([]*Gene, d.ScheduleMap.GetGameSpan(bt.Week)), 
+	chromosome := &Chromosome{Genes: make([]*Gene, bt.Schedule.GetGameSpan(bt.Week)), 
 		FitnessScore: 0, 
 		TotalAcquisitions: 0, 
 		CumProbTracker: 0.0, 
 		DroppedPlayers: make(map[string]d.DroppedPlayer),
 		CurStreamers: make([]d.Player, len(bt.StreamablePlayers)),
 		Week: bt.Week,
+		Template: bt.Template,
+		Limits: bt.Limits,
+		CurrentDay: bt.CurrentDay,
+		Schedule: bt.Schedule,
 	}
 
 	// Make the initial streamers the current streamers
 	copy(chromosome.CurStreamers, bt.StreamablePlayers)
 
 	// Create a gene for each day in the week
-	for i := range d.ScheduleMap.GetGameSpan(bt.Week) {
+	for i := range bt.Schedule.GetGameSpan(bt.Week) {
 		gene := InitGene(bt, i)
 		chromosome.Genes[i] = gene
 	}
@@ -58,8 +67,13 @@ func (c *Chromosome) Populate(bt *t.BaseTeam, rng *rand.Rand) {
 	for day, gene := range c.Genes {
 		acq_count := min(len(bt.UnusedPositions[day]), (rng.Intn(5) / 2) + rng.Intn(2))
 
+		// Days before the current day are already over
+		if day < bt.CurrentDay {
+			acq_count = 0
+		}
+
 		// On the first day, make sure you can't drop initial streamers who are playing
-		if non_playing_streamers_count := gene.Bench.GetLength(); day == 0 && acq_count > non_playing_streamers_count {
+		if non_playing_streamers_count := gene.Bench.GetLength(); day == bt.CurrentDay && acq_count > non_playing_streamers_count {
 			acq_count = non_playing_streamers_count
 		}
 
@@ -74,10 +88,20 @@ func (c *Chromosome) Populate(bt *t.BaseTeam, rng *rand.Rand) {
 			acq_count = len(bt.StreamablePlayers)
 		}
 
+		// Never make more acquisitions than the league allows
+		acq_count = min(acq_count, c.Limits.Available(c.TotalAcquisitions, 0))
+
+		// With a weekly lock every pickup has to be made before the lineup locks on the first day
+		if bt.LockMode.IsWeekly() && day > 0 {
+			acq_count = 0
+		}
+
 		// Create a map of the current (old) streamers
 		old_streamers := make(map[string]d.Player)
+		old_order := make([]d.Player, 0, len(c.CurStreamers))
 		for _, player := range c.CurStreamers {
 			old_streamers[player.Name] = player
+			old_order = append(old_order, player)
 		}
 
 		// Make acquisitions
@@ -90,10 +114,11 @@ func (c *Chromosome) Populate(bt *t.BaseTeam, rng *rand.Rand) {
 
 		}
 
-		// Go through the old streamers and find the ones that were dropped
-		for _, old_player := range old_streamers {
+		// Go through the old streamers in roster order and find the ones that were dropped, so drops are listed the same
+		// way on every run
+		for _, old_player := range old_order {
 			if !u.SliceContainsPlayer(c.CurStreamers, &old_player) {
-				c.DroppedPlayers[old_player.Name] = d.DroppedPlayer{Player: old_player, Countdown: 3}
+				c.DroppedPlayers[old_player.Name] = d.DroppedPlayer{Player: old_player, Countdown: c.Limits.WaiverDays}
 				gene.DroppedPlayers = append(gene.DroppedPlayers, old_player)
 			}
 		}
@@ -111,6 +136,8 @@ func (c *Chromosome) Populate(bt *t.BaseTeam, rng *rand.Rand) {
 		// Decrement the countdown for dropped players
 		c.DecrementDroppedPlayers()
 	}
+
+	c.LockLineups(bt)
 }
 
 // Function to insert a free agent into the chromosome
@@ -118,7 +145,7 @@ func (c *Chromosome) InsertFreeAgent(bt *t.BaseTeam, day int, free_agent d.Playe
 	gene := c.Genes[day]
 
 	// If it is the first day or there are streamers on the bench, drop the worst bench player and find the best positions for the new player
-	if day == 0 || gene.Bench.GetLength() > 0 {
+	if day == bt.CurrentDay || gene.Bench.GetLength() > 0 {
 
 		dropped_player, ok := gene.DropWorstBenchPlayer(); if !ok {
 			return false
@@ -150,8 +177,8 @@ func (c *Chromosome) FindStreamerToDrop(day int, player_to_add d.Player) *d.Play
 	})
 
 	// If there are free posisitions that the incoming player can fill, just return the worst player
-	for _, pos := range player_to_add.ValidPositions {
-		if val, ok := c.Genes[day].FreePositions[pos]; ok && val {
+	for pos, val := range c.Genes[day].FreePositions {
+		if val && c.Template.Accepts(pos, player_to_add) {
 			return &c.CurStreamers[0]
 		}
 	}
@@ -163,12 +190,8 @@ func (c *Chromosome) FindStreamerToDrop(day int, player_to_add d.Player) *d.Play
 		pos := c.Genes[day].GetPosOfPlayer(streamer)
 
 		// Check if the incoming free agent can replace the streamer
-		if pos != "" && pos != "BE" {
-			for _, valid_pos := range player_to_add.ValidPositions {
-				if valid_pos == pos {
-					return &streamer
-				}
-			}
+		if pos != "" && pos != "BE" && c.Template.Accepts(pos, player_to_add) {
+			return &streamer
 		}
 	}
 
@@ -198,14 +221,24 @@ func (c *Chromosome) SlotPlayer(bt *t.BaseTeam, start int, end int, free_agent d
 }
 
 
-// Function to decrement the countdown for dropped players
+// Function to decrement the countdown for dropped players at the end of a day, removing the ones that cleared waivers
 func (c *Chromosome) DecrementDroppedPlayers() {
-	for _, dropped_player := range c.DroppedPlayers {
-		if dropped_player.Countdown > 0 {
-			dropped_player.Countdown--
-		} else {
-			delete(c.DroppedPlayers, dropped_player.Player.Name)
-		}
+	for name := range c.DroppedPlayers {
+		c.DecrementDroppedPlayer(name)
+	}
+}
+
+// Function to decrement the countdown for a single dropped player. The map holds copies, so the updated player is stored back
+func (c *Chromosome) DecrementDroppedPlayer(name string) {
+	dropped_player, ok := c.DroppedPlayers[name]
+	if !ok {
+		return
+	}
+	dropped_player.Countdown--
+	if dropped_player.Countdown <= 0 {
+		delete(c.DroppedPlayers, name)
+	} else {
+		c.DroppedPlayers[name] = dropped_player
 	}
 }
 
@@ -222,7 +255,7 @@ func (c *Chromosome) Mutate(bt *t.BaseTeam, prob float64, rng *rand.Rand) (d.Pla
 		return d.Player{}, d.Player{}, 0, 0
 	}
 	// Add the dropped player to the dropped players map for the start day
-	c.DroppedPlayers[player_to_drop.Name] = d.DroppedPlayer{Player: player_to_drop, Countdown: 3}
+	c.DroppedPlayers[player_to_drop.Name] = d.DroppedPlayer{Player: player_to_drop, Countdown: c.Limits.WaiverDays}
 
 	// Free the position of the player to drop
 	if pos != "BE" {
@@ -237,19 +270,7 @@ func (c *Chromosome) Mutate(bt *t.BaseTeam, prob float64, rng *rand.Rand) (d.Pla
 	for i := start; i < end; i++ {
 
 		// For each day, decrement the countdown for the dropped player
-		if dropped_player, ok := c.DroppedPlayers[player_to_drop.Name]; ok {
-			if dropped_player.Countdown > 0 {
-				dropped_player.Countdown--
-			} else {
-				delete(c.DroppedPlayers, player_to_drop.Name)
-			}
-		}
-
-		// Create a copy of the current streamers
-		old_streamers := make(map[string]d.Player)
-		for _, player := range c.CurStreamers {
-			old_streamers[player.Name] = player
-		}
+		c.DecrementDroppedPlayer(player_to_drop.Name)
 
 		c.Genes[i].RemoveStreamer(player_to_drop)
 		c.Genes[i].SlotPlayer(bt, player_to_add)
@@ -315,6 +336,34 @@ func (c *Chromosome) FindRandomPlayerToDrop(rng *rand.Rand) (d.Player, string, i
 
 }
 
+// Function to rebuild the genes under a weekly lock, where each streamer keeps one open slot for the whole week.
+// A daily lock leaves the genes as they are
+func (c *Chromosome) LockLineups(bt *t.BaseTeam) {
+	if !bt.LockMode.IsWeekly() {
+		return
+	}
+
+	locked := bt.LockStreamers(c.CurStreamers)
+	for _, gene := range c.Genes {
+		gene.Roster = make(map[string]d.Player)
+		gene.FreePositions = make(map[string]bool)
+		gene.Bench = u.Bench{Players: make([]d.Player, 0, 10)}
+
+		for pos := range bt.UnusedPositions[gene.Day] {
+			gene.FreePositions[pos] = true
+		}
+		for pos, player := range locked {
+			gene.Roster[pos] = player
+			gene.FreePositions[pos] = false
+		}
+		for _, streamer := range c.CurStreamers {
+			if !gene.IsPlayerInRoster(streamer) {
+				gene.Bench.AddPlayer(streamer)
+			}
+		}
+	}
+}
+
 // Function to add back the non-streamable players to the chromosome for returning
 func (c *Chromosome) AddBackNonStreamablePlayers(bt *t.BaseTeam) {
 	for day, gene := range c.Genes {
@@ -331,8 +380,7 @@ func (c *Chromosome) AddBackNonStreamablePlayers(bt *t.BaseTeam) {
 
 // Function to print the chromosome
 func (c *Chromosome) Print() {
-	order := []string{"PG", "SG", "SF", "PF", "C", "G", "F", "UT1", "UT2", "UT3"}
-
+	order := c.Template.StartingSlots()
 
 	fmt.Println("Total Acquisitions:", c.TotalAcquisitions)
 	for i := 0; i < len(c.Genes); i++ {
@@ -360,19 +408,35 @@ func (c *Chromosome) Print() {
 // Function to score the fitness of the chromosome
 func (c *Chromosome) ScoreFitness() {
 
-	fitness_score := 0.0
-	penalty_factor := 1.0
-
-	if c.TotalAcquisitions > d.ScheduleMap.GetGameSpan(c.Week) {
-		penalty_factor = 1.0 / math.Pow(1.3, float64(c.TotalAcquisitions - d.ScheduleMap.GetGameSpan(c.Week)))
+	// The acquisition limits are hard constraints, a chromosome that breaks them has no fitness
+	if !c.IsWithinLimits() {
+		c.FitnessScore = 0
+		return
 	}
+
+	// Players locked into a slot for the week only score on days they play, and only the days left in the week count
+	fitness_score := 0.0
 	for _, gene := range c.Genes {
+		if gene.Day < c.CurrentDay {
+			continue
+		}
 		for _, player := range gene.Roster {
-			fitness_score += player.AvgPoints
+			if c.Schedule.IsPlaying(c.Week, gene.Day, player.Team) {
+				fitness_score += c.Schedule.GetPoints(player, c.Week, gene.Day)
+			}
 		}
 	}
 
-	c.FitnessScore = int(fitness_score * penalty_factor)
+	c.FitnessScore = int(fitness_score)
+}
+
+// Function to check if the acquisitions made on each day follow the league's limits
+func (c *Chromosome) IsWithinLimits() bool {
+	daily_counts := make([]int, len(c.Genes))
+	for i, gene := range c.Genes {
+		daily_counts[i] = gene.Acquisitions
+	}
+	return c.Limits.Allows(daily_counts)
 }
 
 // Function to return a slimmed down, defreferenced version of the chromosome
@@ -383,3 +447,15 @@ func (c *Chromosome) Slim() []u.SlimGene {
 	}
 	return slim_chromosome
 }
+
+// Function to make a copy of the chromosome that shares nothing with it, so it can join another population
+func (c *Chromosome) Copy() *Chromosome {
+	chromosome := *c
+	chromosome.Genes = make([]*Gene, len(c.Genes))
+	for i, gene := range c.Genes {
+		chromosome.Genes[i] = gene.Copy()
+	}
+	chromosome.DroppedPlayers = maps.Clone(c.DroppedPlayers)
+	chromosome.CurStreamers = slices.Clone(c.CurStreamers)
+	return &chromosome
+}
@@ -2,7 +2,9 @@ package population
 
 import (
 	"fmt"
+	"maps"
 	"math/rand"
+	"slices"
 	d "v2/data"
 	t "v2/team"
 	u "v2/utils"
@@ -62,16 +64,16 @@ func (g *Gene) InsertStreamablePlayers(bt *t.BaseTeam) {
 // Function to slot a player into the gene
 func (g *Gene) SlotPlayer(bt *t.BaseTeam, streamer d.Player) {
 
-	// If the streamer is not playing, add them to the bench
-	if !d.ScheduleMap.IsPlaying(bt.Week, g.Day, streamer.Team) {
+	// If the streamer is not playing or is projected to rest, add them to the bench
+	if !bt.Schedule.IsPlaying(bt.Week, g.Day, streamer.Team) || bt.Schedule.IsResting(streamer, bt.Week, g.Day) {
 		g.Bench.AddPlayer(streamer)
 		return
 	}
 
 	// Find the matching positions for the player
-	matches := make([]string, 0, len(streamer.ValidPositions))
-	for _, pos := range streamer.ValidPositions {
-		if val, ok := g.FreePositions[pos]; ok && val {
+	matches := make([]string, 0, len(g.FreePositions))
+	for _, pos := range bt.Template.StartingSlots() {
+		if val, ok := g.FreePositions[pos]; ok && val && bt.Template.Accepts(pos, streamer) {
 			matches = append(matches, pos)
 		}
 	}
@@ -114,13 +116,18 @@ func (g *Gene) FindRandomFreeAgent(bt *t.BaseTeam, c *Chromosome, rng *rand.Rand
 		// If a player to replace was passed, make sure the free agent can replace them
 		if replaced.Name != "" {
 			replace_pos := g.GetPosOfPlayer(replaced)
-			if !u.Contains(free_agent.ValidPositions, replace_pos) {
+			if !bt.Template.Accepts(replace_pos, free_agent) {
 				continue
 			}
 		}
 
-		// Check if the free agent is playing
-		if !d.ScheduleMap.IsPlaying(bt.Week, g.Day, free_agent.Team) || free_agent.Injured {
+		// Check if the free agent is playing, isn't injured or resting and has cleared waivers. Under a weekly lock a game later
+		// in the week is enough
+		playing := bt.Schedule.IsPlaying(bt.Week, g.Day, free_agent.Team) && !bt.Schedule.IsResting(free_agent, bt.Week, g.Day)
+		if bt.LockMode.IsWeekly() {
+			playing = bt.Schedule.CountGames(bt.Week, free_agent.Team) > 0 && !free_agent.IsOutBetween(g.Day, bt.Schedule.GetGameSpan(bt.Week))
+		}
+		if !playing || free_agent.IsOnWaivers(g.Day) {
 			continue
 		}
 
@@ -130,8 +137,8 @@ func (g *Gene) FindRandomFreeAgent(bt *t.BaseTeam, c *Chromosome, rng *rand.Rand
 		}
 
 		// Check if the free agent can be rostered on the current day
-		for _, pos := range free_agent.ValidPositions {
-			if val, ok := g.FreePositions[pos]; ok && val {
+		for pos, val := range g.FreePositions {
+			if val && bt.Template.Accepts(pos, free_agent) {
 				return free_agent
 			}
 		}
@@ -289,6 +296,19 @@ func (g *Gene) Slim() u.SlimGene {
 	return slim_gene
 }
 
+// Function to make a copy of the gene that shares nothing with it
+func (g *Gene) Copy() *Gene {
+	return &Gene{
+		Roster: maps.Clone(g.Roster),
+		FreePositions: maps.Clone(g.FreePositions),
+		NewPlayers: slices.Clone(g.NewPlayers),
+		DroppedPlayers: slices.Clone(g.DroppedPlayers),
+		Day: g.Day,
+		Acquisitions: g.Acquisitions,
+		Bench: u.Bench{Players: slices.Clone(g.Bench.Players)},
+	}
+}
+
 // Function to check is a player is in the roster
 func (g *Gene) IsPlayerInRoster(player d.Player) bool {
 	
@@ -302,9 +322,10 @@ func (g *Gene) IsPlayerInRoster(player d.Player) bool {
 }
 
 // Function to print the gene
-func (g *Gene) Print() {
+func (g *Gene) Print(template d.RosterTemplate) {
 	
-	order := []string{"PG", "SG", "SF", "PF", "C", "G", "F", "UT1", "UT2", "UT3"}
+	// Print the starting slots in the template's order so the output follows the lineup layout
+	order := template.StartingSlots()
 
 	for _, pos := range order {
 		if val, ok := g.FreePositions[pos]; ok && val {
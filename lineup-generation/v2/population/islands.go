@@ -0,0 +1,179 @@
+package population
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	t "v2/team"
+	u "v2/utils"
+)
+
+// Struct for populations that evolve side by side and trade their fittest chromosomes every few generations, which
+// spreads good plans without letting one of them take over every island
+type IslandModel struct {
+	Islands []*EvolutionManager
+	Config  u.Algorithm
+}
+
+// Function to create [config.Islands] populations whose seeds are all drawn from [seed]
+func InitIslandModel(bt *t.BaseTeam, config u.Algorithm, seed int64) *IslandModel {
+	rng := rand.New(rand.NewSource(seed))
+	im := &IslandModel{Islands: make([]*EvolutionManager, config.Islands), Config: config}
+	for i := range im.Islands {
+		im.Islands[i] = InitPopulationWithConfig(bt, config, rng.Int63())
+	}
+	return im
+}
+
+// Function to evolve the islands concurrently for the configured number of generations, migrating between them every
+// interval. It stops early once every island has stalled, and returns the context's error when the context is
+// cancelled, the islands keep the chromosomes evolved so far
+func (im *IslandModel) Run(ctx context.Context, bt *t.BaseTeam) error {
+
+	for generation := 0; generation < im.Config.Generations; {
+
+		// Evolve every island up to the next migration, without an interval they never migrate
+		epoch := im.Config.Generations - generation
+		if im.Config.MigrationInterval > 0 {
+			epoch = min(epoch, im.Config.MigrationInterval)
+		}
+		if err := im.Evolve(ctx, bt, epoch); err != nil {
+			return err
+		}
+		if im.IsStalled() {
+			return nil
+		}
+
+		generation += epoch
+		if generation < im.Config.Generations {
+			im.Migrate()
+		}
+	}
+
+	return nil
+}
+
+// Function to keep evolving the islands until the context is done or every island has stalled, ignoring the configured
+// number of generations. It returns the context's error when the context ends the run
+func (im *IslandModel) RunUntilDone(ctx context.Context, bt *t.BaseTeam) error {
+
+	// Without a migration interval the islands evolve one generation at a time and never migrate
+	epoch := max(im.Config.MigrationInterval, 1)
+	for {
+		if err := im.Evolve(ctx, bt, epoch); err != nil {
+			return err
+		}
+		if im.IsStalled() {
+			return nil
+		}
+		if im.Config.MigrationInterval > 0 {
+			im.Migrate()
+		}
+	}
+}
+
+// Function to evolve every island concurrently for [generations] generations, checking the context between them. An
+// island that has stalled sits out
+func (im *IslandModel) Evolve(ctx context.Context, bt *t.BaseTeam, generations int) error {
+	var wg sync.WaitGroup
+	for _, island := range im.Islands {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range generations {
+				if ctx.Err() != nil || island.IsStalled() {
+					return
+				}
+				island.Evolve(bt)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// Function to copy the fittest chromosomes of each island to its neighbors in the topology, where they replace the
+// least fit ones
+func (im *IslandModel) Migrate() {
+	if len(im.Islands) < 2 || im.Config.Migrants == 0 {
+		return
+	}
+
+	// Pick every island's emigrants before any arrive, so the order the islands are visited in doesn't matter
+	emigrants := make([][]*Chromosome, len(im.Islands))
+	for i, island := range im.Islands {
+		island.SortByFitness()
+		count := min(im.Config.Migrants, island.NumChromosomes)
+		emigrants[i] = append([]*Chromosome{}, island.Population[island.NumChromosomes-count:]...)
+	}
+
+	for i, island := range im.Islands {
+		var arrivals []*Chromosome
+		switch im.Config.Topology {
+		case u.TopologyFull:
+			for j := range im.Islands {
+				if j != i {
+					arrivals = append(arrivals, emigrants[j]...)
+				}
+			}
+			sort.SliceStable(arrivals, func(k, l int) bool {
+				return arrivals[k].FitnessScore > arrivals[l].FitnessScore
+			})
+			arrivals = arrivals[:min(im.Config.Migrants, len(arrivals))]
+		default:
+			arrivals = emigrants[(i+len(im.Islands)-1)%len(im.Islands)]
+		}
+
+		// The population is sorted, so the least fit chromosomes are at the front. Each island gets its own copies, and
+		// one that stalled starts again if they beat its best
+		for j, chromosome := range arrivals[:min(len(arrivals), island.NumChromosomes)] {
+			island.Population[j] = chromosome.Copy()
+		}
+		if island.TrackBest() {
+			island.Stalled = 0
+		}
+	}
+}
+
+// Function to combine the islands into one population to pick the plan from
+func (im *IslandModel) Combine() *EvolutionManager {
+	ev := &EvolutionManager{Population: make([]*Chromosome, 0, len(im.Islands)*im.Config.PopulationSize), Config: im.Config}
+	for _, island := range im.Islands {
+		ev.Population = append(ev.Population, island.Population...)
+	}
+	ev.NumChromosomes = len(ev.Population)
+	return ev
+}
+
+// Function to get the fittest chromosome any island has seen that follows the league's acquisition rules, nil when
+// none has
+func (im *IslandModel) Best() *Chromosome {
+	var best *Chromosome
+	for _, island := range im.Islands {
+		if island.Best != nil && (best == nil || island.Best.FitnessScore > best.FitnessScore) {
+			best = island.Best
+		}
+	}
+	return best
+}
+
+// Function that returns whether every island has stalled
+func (im *IslandModel) IsStalled() bool {
+	for _, island := range im.Islands {
+		if !island.IsStalled() {
+			return false
+		}
+	}
+	return true
+}
+
+// Function to get the stats for every generation of each island
+func (im *IslandModel) Stats() [][]u.GenerationStats {
+	stats := make([][]u.GenerationStats, len(im.Islands))
+	for i, island := range im.Islands {
+		stats[i] = island.Stats
+	}
+	return stats
+}
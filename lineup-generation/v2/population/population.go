@@ -15,48 +15,62 @@ import (
 type EvolutionManager struct {
 	Population 	   []*Chromosome
 	NumChromosomes int
+
+	// Every random draw comes from this generator, so the same seed and team always evolve the same way
+	Seed int64
+	Rng  *rand.Rand
+
+	// Settings for mutation, selection and elitism
+	Config u.Algorithm
+
+	// Fittest chromosome seen so far that follows the league's acquisition rules, nil until one is found
+	Best *Chromosome
+
+	// Stats for the first population and every generation since, and how many generations in a row haven't improved on
+	// the best chromosome
+	Stats   []u.GenerationStats
+	Stalled int
 }
 
-// Function to create a new population
+// Function to create a new population with a seed from the clock
 func InitPopulation(bt *t.BaseTeam, size int) *EvolutionManager {
+	return InitPopulationWithSeed(bt, size, time.Now().UnixNano())
+}
+
+// Function to create a new population whose random draws all come from [seed]
+func InitPopulationWithSeed(bt *t.BaseTeam, size int, seed int64) *EvolutionManager {
+	config := u.DefaultAlgorithm()
+	config.PopulationSize = size
+	return InitPopulationWithConfig(bt, config, seed)
+}
+
+// Function to create a new population that evolves with the given settings and whose random draws all come from [seed]
+func InitPopulationWithConfig(bt *t.BaseTeam, config u.Algorithm, seed int64) *EvolutionManager {
+	size := config.PopulationSize
 
 	// Create a new population
-	ev := &EvolutionManager{Population: make([]*Chromosome, size), NumChromosomes: size}
+	ev := &EvolutionManager{Population: make([]*Chromosome, size), NumChromosomes: size, Seed: seed, Rng: rand.New(rand.NewSource(seed)), Config: config}
 
 	var wg sync.WaitGroup
-	ch := make(chan *Chromosome)
 
-	// Create [size] goroutines to generate chromosomes concurrently
+	// Create [size] goroutines to generate chromosomes concurrently. Each one gets its own generator seeded up front and
+	// fills its own place in the population, so the result doesn't depend on which goroutine finishes first
 	for i := 0; i < size; i++ {
+		rng := rand.New(rand.NewSource(ev.Rng.Int63()))
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 
 			chromosome := InitChromosome(bt)
-
-			// Create random number generator
-			seed := time.Now().UnixNano() + int64(i)
-			rng := rand.New(rand.NewSource(seed))
-
 			chromosome.Populate(bt, rng)
 			chromosome.ScoreFitness()
-			
-			ch <- chromosome
-		}()
-	}
 
-	// Wait for all goroutines to finish and collect the chromosomes
-	go func() {
-		wg.Wait()
-		close(ch)
-	}()
-
-	// Collect the chromosomes from the channel
-	i := 0
-	for chromosome := range ch {
-		ev.Population[i] = chromosome
-		i++
+			ev.Population[i] = chromosome
+		}()
 	}
+	wg.Wait()
+	ev.TrackBest()
+	ev.RecordStats()
 
 	return ev
 }
@@ -70,15 +84,15 @@ func (ev *EvolutionManager) Evolve(bt *t.BaseTeam) {
 
 	next_generation := make([]*Chromosome, ev.NumChromosomes)
 
-	// Elitism: keep the best chromosome from the previous generation
-	next_generation[ev.NumChromosomes-1] = ev.Population[ev.NumChromosomes-1]
+	// Elitism: keep the best chromosomes from the previous generation
+	elites := min(ev.Config.Elitism, ev.NumChromosomes)
+	copy(next_generation[ev.NumChromosomes-elites:], ev.Population[ev.NumChromosomes-elites:])
 
 	// Generate the rest of the chromosomes
-	for i := 0; i < ev.NumChromosomes-1; i++ {
+	for i := 0; i < ev.NumChromosomes-elites; i++ {
 
-		// Create random seed
-		seed := rand.NewSource(time.Now().UnixNano() + int64(i))
-		rng := rand.New(seed)
+		// Create random number generator from the population's generator
+		rng := rand.New(rand.NewSource(ev.Rng.Int63()))
 
 		// Selection: select two parents
 		parent1 := ev.SelectParent(1, rng)
@@ -88,8 +102,7 @@ func (ev *EvolutionManager) Evolve(bt *t.BaseTeam) {
 		child := ev.Crossover(bt, parent1, parent2, rng)
 		
 		// Mutation: mutate the child
-		prob_of_mutation := 0.20
-		child.Mutate(bt, prob_of_mutation, rng)
+		child.Mutate(bt, ev.Config.MutationRate, rng)
 		child.ScoreFitness()
 
 		next_generation[i] = child
@@ -97,14 +110,34 @@ func (ev *EvolutionManager) Evolve(bt *t.BaseTeam) {
 
 	// Replace the old population with the new population
 	ev.Population = next_generation
+	if ev.TrackBest() {
+		ev.Stalled = 0
+	} else {
+		ev.Stalled++
+	}
+	ev.RecordStats()
 
 }
 
-// Function to assign cumulative probabilities to the chromosomes
+// Function to remember the fittest chromosome that follows the league's acquisition rules, so a run that's cut off can
+// still return it after later generations lose it. Returns whether the best chromosome improved
+func (ev *EvolutionManager) TrackBest() bool {
+	improved := false
+	for _, chromosome := range ev.Population {
+		if chromosome.IsWithinLimits() && (ev.Best == nil || chromosome.FitnessScore > ev.Best.FitnessScore) {
+			ev.Best = chromosome
+			improved = true
+		}
+	}
+	return improved
+}
+
+// Function to assign cumulative probabilities to the chromosomes, the selection pressure decides how much more likely
+// the fitter ones are
 func (ev *EvolutionManager) AssignCumProbs() {
 
 	GetProbability := func(x int) float64 {
-		return math.Pow(float64(x) / float64(ev.NumChromosomes), 1.5) + 0.02
+		return math.Pow(float64(x) / float64(ev.NumChromosomes), ev.Config.SelectionPressure) + ev.Config.SelectionOffset
 	}
 
 	cum_prob := GetProbability(0)
@@ -131,7 +164,7 @@ func (ev *EvolutionManager) SelectParent(num int, rng *rand.Rand) *Chromosome {
 	switch num {
 	case 1:
 		// Select a parent using roulette wheel selection
-		rand_num := rand.Float64() * ev.Population[ev.NumChromosomes - 1].CumProbTracker
+		rand_num := rng.Float64() * ev.Population[ev.NumChromosomes - 1].CumProbTracker
 
 		for _, chromosome := range ev.Population {
 			if chromosome.CumProbTracker >= rand_num {
@@ -139,19 +172,18 @@ func (ev *EvolutionManager) SelectParent(num int, rng *rand.Rand) *Chromosome {
 			}
 		}
 	case 2:
-		// Select a parent using tournament selection
-		tournament := make([][5]*Chromosome, 3)
+		// Select a parent using tournament selection, the fittest chromosome drawn into each group wins it
+		winners := make([]*Chromosome, ev.Config.TournamentGroups)
 
-		for i := 0; i < 3; i++ {
-			for j := 0; j < 5; j++ {
+		for i := range winners {
+			for range ev.Config.TournamentSize {
 				rand_num := rng.Intn(ev.NumChromosomes)
-				tournament[i][j] = ev.Population[rand_num]
+				if winners[i] == nil || ev.Population[rand_num].FitnessScore > winners[i].FitnessScore {
+					winners[i] = ev.Population[rand_num]
+				}
 			}
-			sort.Slice(tournament[i][:], func(k, l int) bool {
-				return tournament[i][k].FitnessScore < tournament[i][l].FitnessScore
-			})
 		}
-		return tournament[rng.Intn(3)][1]
+		return winners[rng.Intn(len(winners))]
 	}
 
 	return ev.Population[ev.NumChromosomes - 1]
@@ -174,16 +206,19 @@ func (ev *EvolutionManager) Crossover(bt *t.BaseTeam, parent1, parent2 *Chromoso
 
 		// Create a copy of the current streamers
 		old_streamers := make(map[string]d.Player)
+		old_order := make([]d.Player, 0, len(child.CurStreamers))
 		for _, player := range child.CurStreamers {
 			old_streamers[player.Name] = player
+			old_order = append(old_order, player)
 		}
 
 		ev.MixGenes(bt, child, parent1.Genes[i], parent2.Genes[i], rng)
 
-		// Go through the old streamers and find the ones that were dropped
-		for _, old_player := range old_streamers {
+		// Go through the old streamers in roster order and find the ones that were dropped, so drops are listed the same
+		// way on every run
+		for _, old_player := range old_order {
 			if !u.SliceContainsPlayer(child.CurStreamers, &old_player) {
-				child.DroppedPlayers[old_player.Name] = d.DroppedPlayer{Player: old_player, Countdown: 3}
+				child.DroppedPlayers[old_player.Name] = d.DroppedPlayer{Player: old_player, Countdown: child.Limits.WaiverDays}
 				gene.DroppedPlayers = append(gene.DroppedPlayers, old_player)
 			}
 		}
@@ -196,8 +231,12 @@ func (ev *EvolutionManager) Crossover(bt *t.BaseTeam, parent1, parent2 *Chromoso
 				child.TotalAcquisitions++
 			}
 		}
+
+		// Decrement the countdown for dropped players once the day's drops are in, the same as Populate
+		child.DecrementDroppedPlayers()
 	}
 
+	child.LockLineups(bt)
 
 	return child
 }
@@ -205,6 +244,16 @@ func (ev *EvolutionManager) Crossover(bt *t.BaseTeam, parent1, parent2 *Chromoso
 // Function to mix the genes of two parent chromosomes
 func (ev *EvolutionManager) MixGenes(bt *t.BaseTeam, child *Chromosome, parent1, parent2 *Gene, rng *rand.Rand) {
 
+	// With a weekly lock every pickup has to be made before the lineup locks on the first day
+	if bt.LockMode.IsWeekly() && parent1.Day > 0 {
+		return
+	}
+
+	// Days before the current day are already over
+	if parent1.Day < bt.CurrentDay {
+		return
+	}
+
 	// Create a list of all the new players in the parent genes
 	new_players := make([]d.Player, 0, len(parent1.NewPlayers) + len(parent2.NewPlayers))
 	new_players = append(new_players, parent1.NewPlayers...)
@@ -226,8 +275,14 @@ func (ev *EvolutionManager) MixGenes(bt *t.BaseTeam, child *Chromosome, parent1,
 		num_players = 1
 	}
 
+	// Never try more pickups than the league allows, the acquisitions for this day haven't been counted yet
+	num_players = min(num_players, len(new_players), child.Limits.Available(child.TotalAcquisitions, 0))
+
 	// Add the new players to the child
 	for i := 0; i < num_players; i++ {
+		if new_players[i].IsOnWaivers(parent1.Day) {
+			continue
+		}
 		if p, ok := child.DroppedPlayers[new_players[i].Name]; (!ok || (p.Player.Name != "" && p.Countdown == 0)) && !child.Genes[parent1.Day].IsPlayerInGene(new_players[i]) {
 			child.InsertFreeAgent(bt, parent1.Day, new_players[i])
 			// child.Genes[parent1.Day].NewPlayers = append(child.Genes[parent1.Day].NewPlayers, new_players[i])
@@ -235,6 +290,4 @@ func (ev *EvolutionManager) MixGenes(bt *t.BaseTeam, child *Chromosome, parent1,
 			// child.TotalAcquisitions++
 		}
 	}
-
-	child.DecrementDroppedPlayers()
 }
\ No newline at end of file
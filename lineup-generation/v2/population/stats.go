@@ -0,0 +1,53 @@
+package population
+
+import (
+	"strings"
+	u "v2/utils"
+)
+
+// Function to record the stats of the current population as the next generation
+func (ev *EvolutionManager) RecordStats() {
+	stats := u.GenerationStats{Generation: len(ev.Stats)}
+	if ev.NumChromosomes == 0 {
+		ev.Stats = append(ev.Stats, stats)
+		return
+	}
+
+	total := 0
+	plans := make(map[string]bool)
+	stats.Best, stats.Worst = ev.Population[0].FitnessScore, ev.Population[0].FitnessScore
+	for _, chromosome := range ev.Population {
+		stats.Best = max(stats.Best, chromosome.FitnessScore)
+		stats.Worst = min(stats.Worst, chromosome.FitnessScore)
+		total += chromosome.FitnessScore
+		if !chromosome.IsWithinLimits() {
+			stats.Infeasible++
+		}
+		plans[chromosome.MovesKey()] = true
+	}
+	stats.Mean = float64(total) / float64(ev.NumChromosomes)
+	stats.Diversity = float64(len(plans)) / float64(ev.NumChromosomes)
+
+	ev.Stats = append(ev.Stats, stats)
+}
+
+// Function that returns whether the population has gone the configured number of generations without improving, it
+// never stalls when early stopping is off
+func (ev *EvolutionManager) IsStalled() bool {
+	return ev.Config.StallGenerations > 0 && ev.Stalled >= ev.Config.StallGenerations
+}
+
+// Function to get a key for the moves a chromosome makes, two chromosomes with the same key make the same plan
+func (c *Chromosome) MovesKey() string {
+	var key strings.Builder
+	for _, gene := range c.Genes {
+		for _, player := range gene.NewPlayers {
+			key.WriteString("+" + player.Name)
+		}
+		for _, player := range gene.DroppedPlayers {
+			key.WriteString("-" + player.Name)
+		}
+		key.WriteString("|")
+	}
+	return key.String()
+}
@@ -1,9 +1,11 @@
 package team
 
 import (
+	"math"
 	"sort"
 	d "v2/data"
 	l "v2/resources"
+	u "v2/utils"
 )
 
 type BaseTeam struct {
@@ -14,27 +16,39 @@ type BaseTeam struct {
 	StreamablePlayers []d.Player
 	Score             int
 	Week              int
+	Template          d.RosterTemplate
+	SlotWeights       map[string]int
+	SlottingMode      d.SlottingMode
+	Limits            d.AcquisitionLimits
+	LockMode          d.LockMode
+
+	// Season schedule the team is planned against, each request brings its own so requests don't share it
+	Schedule          *d.SeasonSchedule
+
+	// Day of the week the plan starts on, the days before it are already over and stay as they are
+	CurrentDay        int
 }
 
-func InitBaseTeam(rosterData []d.Player, freeAgentData []d.Player, week int, threshold float64) *BaseTeam {
+func InitBaseTeam(schedule *d.SeasonSchedule, rosterData []d.Player, freeAgentData []d.Player, week int, threshold float64, template d.RosterTemplate, mode d.SlottingMode, lock d.LockMode) *BaseTeam {
 
-	bt := &BaseTeam{}
+	bt := &BaseTeam{Template: template, SlotWeights: template.Weights(), Limits: d.DefaultAcquisitionLimits(schedule.GetGameSpan(week)), LockMode: lock, Schedule: schedule}
 	bt.RosterMap = d.PlayersToMap(rosterData)
 	bt.FreeAgents = freeAgentData
-	bt.OptimizeSlotting(week, threshold)
+	bt.Week = week
+	bt.OptimizeSlotting(week, threshold, mode)
 	bt.FindUnusedPositions()
 	bt.CalculateOptimalScore()
-	bt.Week = week
 
 	return bt
 }
 
-func InitBaseTeamMock(week int, threshold float64) *BaseTeam {
+func InitBaseTeamMock(schedule *d.SeasonSchedule, week int, threshold float64) *BaseTeam {
 
-	bt := &BaseTeam{}
+	template := d.DefaultRosterTemplate()
+	bt := &BaseTeam{Template: template, SlotWeights: template.Weights(), Limits: d.DefaultAcquisitionLimits(schedule.GetGameSpan(week)), Schedule: schedule}
 	bt.RosterMap = l.LoadRosterMap("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/resources/mock_roster.json")
 	bt.FreeAgents = l.LoadFreeAgents("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/resources/mock_freeagents.json")
-	bt.OptimizeSlotting(week, threshold)
+	bt.OptimizeSlotting(week, threshold, d.SlotByRestrictiveness)
 	bt.FindUnusedPositions()
 	bt.CalculateOptimalScore()
 	bt.Week = week
@@ -43,15 +57,19 @@ func InitBaseTeamMock(week int, threshold float64) *BaseTeam {
 }
 
 
-// Finds available slots and players to experiment with on a roster when considering undroppable players and restrictive positions
-func (t *BaseTeam) OptimizeSlotting(week int, threshold float64) {
+// Finds available slots and players to experiment with on a roster when considering undroppable players and restrictive positions.
+// The mode decides whether the most restrictive slots or the most points come first
+func (t *BaseTeam) OptimizeSlotting(week int, threshold float64, mode d.SlottingMode) {
+
+	t.SlottingMode = mode
 
-	// Convert RosterMap to slices and abstract out IR spot. For the first day, pass all players to get_available_slots
+	// Split the roster into the players worth keeping and the streamers
 	var streamable_players []d.Player
 	var sorted_good_players []d.Player
 	for _, player := range t.RosterMap {
 
-		if player.Injured {
+		// Players who are out all week are left out, the others come back into the lineup on the day they return
+		if player.IsOutBetween(0, t.Schedule.GetGameSpan(week)) {
 			continue
 		}
 
@@ -62,26 +80,43 @@ func (t *BaseTeam) OptimizeSlotting(week int, threshold float64) {
 		}
 	}
 
-	// Sort good players by average points
+	// Sort good players by average points. The roster is a map, so ties are broken by name to keep the order the same on
+	// every run
 	sort.Slice(sorted_good_players, func(i, j int) bool {
-		return sorted_good_players[i].AvgPoints > sorted_good_players[j].AvgPoints
+		return byPointsThenName(sorted_good_players[i], sorted_good_players[j])
 	})
 
 	return_table := make(map[int]map[string]d.Player)
 
-	// Fill return table and put extra IR players on bench
-	for i := range d.ScheduleMap.GetGameSpan(week) {
+	// Fill return table, injured players stay out of it until the day they return. With a weekly lock the same lineup is kept every day
+	var weekly_lineup map[string]d.Player
+	if t.LockMode.IsWeekly() {
+		weekly_lineup = t.FillSlots(t.MatchWeek(sorted_good_players, week))
+	}
+	for i := range t.Schedule.GetGameSpan(week) {
+		if weekly_lineup != nil {
+			return_table[i] = weekly_lineup
+			continue
+		}
 		return_table[i] = t.GetAvailableSlots(sorted_good_players, i, week)
 	}
 
-	// Sort the streamable players by average points
+	// Sort the streamable players by average points, ties broken by name as well
 	sort.Slice(streamable_players, func(i, j int) bool {
-		return streamable_players[i].AvgPoints > streamable_players[j].AvgPoints
+		return byPointsThenName(streamable_players[i], streamable_players[j])
 	})
 	t.StreamablePlayers = streamable_players
 	t.OptimalSlotting = return_table
 }
 
+// Function to order players by average points, most first, and by name when the averages are tied
+func byPointsThenName(a d.Player, b d.Player) bool {
+	if a.AvgPoints != b.AvgPoints {
+		return a.AvgPoints > b.AvgPoints
+	}
+	return a.Name < b.Name
+}
+
 // Struct for keeping track of state across recursive function calls to allow for early exit
 type FitPlayersContext struct {
 	BestLineup map[string]d.Player
@@ -93,61 +128,113 @@ type FitPlayersContext struct {
 // Function to get available slots for a given day
 func (t *BaseTeam) GetAvailableSlots(players []d.Player, day int, week int) map[string]d.Player {
 
-	// Priority order of most restrictive positions to funnel streamers into flexible positions
-	position_order := []string{"PG", "SG", "SF", "PF", "G", "F", "C", "UT1", "UT2", "UT3", "BE1", "BE2", "BE3"} // For players playing
-	
 	var playing []d.Player
 
 	for _, player := range players {
 
-		// Checks if the player is playing on the given day
-		if d.ScheduleMap.IsPlaying(week, day, player.Team){
+		// Checks if the player is playing on the given day and isn't projected to rest
+		if t.Schedule.IsPlaying(week, day, player.Team) && !t.Schedule.IsResting(player, week, day) {
 			playing = append(playing, player)
 		}
 	}
 
-	// Find most restrictive positions for players playing
-	optimal_slotting := func (playing []d.Player) map[string]d.Player {
+	return t.FillSlots(t.MatchDay(playing, day, week))
+}
 
-		sort.Slice(playing, func(i, j int) bool {
-			return len(playing[i].ValidPositions) < len(playing[j].ValidPositions)
-		})
+// Function to fill a matched lineup out with empty players for the unused positions except for bench spots
+func (t *BaseTeam) FillSlots(lineup map[string]d.Player) map[string]d.Player {
 
-		// Create struct to keep track of state across recursive function calls
-		max_score := t.CalculateMaxScore(playing)
-		p_context := &FitPlayersContext{
-			BestLineup: make(map[string]d.Player), 
-			TopScore: 0, 
-			MaxScore: max_score, 
-			EarlyExit: false,
+	response := make(map[string]d.Player)
+	for _, pos := range t.Template.SlotOrder() {
+		if value, ok := lineup[pos]; ok {
+			response[pos] = value
+			continue
 		}
-	
-		// Recursive function call
-		t.FitPlayers(playing, make(map[string]d.Player), position_order, p_context, 0)
-	
-		// Create response map and fill with best lineup or empty strings for unused positions except for bench spots
-		response := make(map[string]d.Player)
-		filter := map[string]bool{"BE1": true, "BE2": true, "BE3": true}
-		for _, pos := range position_order {
+		if !t.Template.IsBench(pos) {
+			response[pos] = d.Player{}
+		}
+	}
+
+	return response
+}
+
+// Function to slot players as a maximum weight bipartite matching between players and slots. By default restrictiveness
+// comes first and ties are broken by putting the best players in starting slots, the points mode flips the two
+func (t *BaseTeam) MatchPlayers(players []d.Player) map[string]d.Player {
+	return t.matchPlayers(players, func(player d.Player) float64 {
+		return player.AvgPoints
+	})
+}
+
+// Function to slot the players playing on [day], where each player is worth his projected points for that game
+func (t *BaseTeam) MatchDay(players []d.Player, day int, week int) map[string]d.Player {
+	return t.matchPlayers(players, func(player d.Player) float64 {
+		return t.Schedule.GetPoints(player, week, day)
+	})
+}
+
+// Function to slot players into one lineup for the whole week, where each player is worth his points over every game he plays
+func (t *BaseTeam) MatchWeek(players []d.Player, week int) map[string]d.Player {
+	return t.matchPlayers(players, func(player d.Player) float64 {
+		return t.Schedule.WeeklyPoints(player, week)
+	})
+}
+
+// Function to build and solve the matching between players and slots, using [points] as the value of starting a player
+// v3/helpers/setup_state.go builds the same matching for v3's players, a change here usually belongs there too
+func (t *BaseTeam) matchPlayers(players []d.Player, points func(d.Player) float64) map[string]d.Player {
+
+	position_order := t.Template.SlotOrder()
+
+	// Points are compared in hundredths so both objectives stack exactly, the mode decides which one comes first
+	total_points := 0.0
+	for _, player := range players {
+		total_points += math.Round(max(points(player), 0) * 100)
+	}
+	total_weight := 0.0
+	for _, pos := range position_order {
+		total_weight += float64(t.SlotWeights[pos])
+	}
 
-			if value, ok := p_context.BestLineup[pos]; ok {
-				response[pos] = value
+	// Slot order lists the starting slots first, anything after them is a bench slot that takes anyone
+	starting := len(t.Template.Slots)
+	by_points := t.SlottingMode == d.SlotByPoints
+	weights := make([][]float64, len(players))
+	for i, player := range players {
+		weights[i] = make([]float64, len(position_order))
+		for j, pos := range position_order {
+			if j < starting && !t.Template.Accepts(pos, player) {
+				weights[i][j] = math.Inf(-1)
 				continue
 			}
-			if _, ok := filter[pos]; !ok {
-				response[pos] = d.Player{}
+
+			// Only players in starting slots score points
+			weight := float64(t.SlotWeights[pos])
+			value := 0.0
+			if j < starting {
+				value = math.Round(max(points(player), 0) * 100)
 			}
-		}
 
-		return response
-	
-	}(playing)
+			if by_points {
+				weights[i][j] = value*(total_weight+1) + weight
+			} else {
+				weights[i][j] = weight*(total_points+1) + value
+			}
+		}
+	}
 
-	return optimal_slotting
+	lineup := make(map[string]d.Player)
+	for i, j := range u.MaxWeightAssignment(weights) {
+		if j >= 0 {
+			lineup[position_order[j]] = players[i]
+		}
+	}
 
+	return lineup
 }
 
-// Recursive backtracking function to find most restrictive positions for players
+// Recursive backtracking function to find most restrictive positions for players. Superseded by MatchPlayers
+// for daily slotting and kept as a reference for tests and benchmarks, like its twin in v3/helpers/setup_state.go
 func (t *BaseTeam) FitPlayers(players []d.Player, cur_lineup map[string]d.Player, position_order []string, ctx *FitPlayersContext, index int) {
 
 	// If we have found a lineup that has the max score, we can send returns to all other recursive calls
@@ -173,10 +260,13 @@ func (t *BaseTeam) FitPlayers(players []d.Player, cur_lineup map[string]d.Player
 	}
 
 	// If we have not gone through all players, try to fit the rest of the players in the lineup
+	if index >= len(position_order) {
+		return // No more positions to try
+	}
 	position := position_order[index]
 	found_player := false
 	for _, player := range players {
-		if player.PlaysPosition(position) {
+		if t.Template.Accepts(position, player) {
 			found_player = true
 			cur_lineup[position] = player
 
@@ -204,20 +294,10 @@ func (t *BaseTeam) FitPlayers(players []d.Player, cur_lineup map[string]d.Player
 // Function to score a roster based on restricitveness of positions
 func (t *BaseTeam) ScoreRoster(roster map[string]d.Player) int {
 
-	// Scoring system
-	score_map := make(map[string]int)
-
-	scoring_groups := [][]string{{"PG", "SG", "SF", "PF"}, {"G", "F"}, {"C"}, {"UT1", "UT2", "UT3"}, {"BE1", "BE2", "BE3"}}
-	for score, group := range scoring_groups {
-		for _, position := range group {
-			score_map[position] = 5 - score
-		}
-	}
-
-	// Score roster
+	// Score roster using the template's slot weights
 	score := 0
 	for pos := range roster {
-		score += score_map[pos]
+		score += t.SlotWeights[pos]
 	}
 
 	return score
@@ -226,28 +306,40 @@ func (t *BaseTeam) ScoreRoster(roster map[string]d.Player) int {
 // Function to calculate the max restrictiveness score for a given set of players
 func (t *BaseTeam) CalculateMaxScore(players []d.Player) int {
 
-	size := len(players)
-
-	// Max score calulation corresponding with scoring_groups in score_roster
-	switch {
-		case size <= 4:
-			return size * 5
-		case size <= 6:
-			return 20 + ((size - 4) * 4)
-		case size <= 7:
-			return 28 + ((size - 6) * 3)
-		case size <= 10:
-			return 31 + ((size - 7) * 2)
-		default:
-			return 37 + (size - 10)
+	position_order := t.Template.SlotOrder()
+
+	// Each player can at best land in the highest weighted slot he is eligible for
+	player_bound := 0
+	for _, player := range players {
+		best := 0
+		for _, pos := range position_order {
+			if t.SlotWeights[pos] > best && t.Template.Accepts(pos, player) {
+				best = t.SlotWeights[pos]
+			}
+		}
+		player_bound += best
+	}
+
+	// Each slot can only be used once, so at best the players fill the highest weighted slots
+	weights := make([]int, 0, len(position_order))
+	for _, pos := range position_order {
+		weights = append(weights, t.SlotWeights[pos])
 	}
+	sort.Sort(sort.Reverse(sort.IntSlice(weights)))
+
+	slot_bound := 0
+	for i := 0; i < len(players) && i < len(weights); i++ {
+		slot_bound += weights[i]
+	}
+
+	return min(player_bound, slot_bound)
 }
 
 // Function to get the unused positions from the optimal slotting for good players playing for the week
 func (t *BaseTeam) FindUnusedPositions() {
 
 	// Order that the slice should be in
-	order := []string{"PG", "SG", "SF", "PF", "C", "G", "F", "UT1", "UT2", "UT3"}
+	order := t.Template.StartingSlots()
 
 	// Create map to keep track of unused positions
 	unused_positions := make(map[int]map[string]bool)
@@ -272,12 +364,41 @@ func (t *BaseTeam) FindUnusedPositions() {
 	t.UnusedPositions = unused_positions
 }
 
-// Function to calculate the score of the optimal players for the week
+// Function to give each streamer one open slot for the whole week under a weekly lock. Streamers with the most points
+// over the week get first pick, anyone left over sits on the bench
+func (t *BaseTeam) LockStreamers(streamers []d.Player) map[string]d.Player {
+
+	sorted := append([]d.Player{}, streamers...)
+	games := func(player d.Player) float64 {
+		return t.Schedule.WeeklyPoints(player, t.Week)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return games(sorted[i]) > games(sorted[j])
+	})
+
+	// Open slots are the same every day of a locked week, so the first day decides where each streamer goes
+	locked := make(map[string]d.Player)
+	for _, streamer := range sorted {
+		for _, pos := range t.Template.StartingSlots() {
+			if _, taken := locked[pos]; !taken && t.UnusedPositions[0][pos] && t.Template.Accepts(pos, streamer) {
+				locked[pos] = streamer
+				break
+			}
+		}
+	}
+
+	return locked
+}
+
+// Function to calculate the score of the optimal players for the week. Players locked into a lineup only score on days they play
 func (t *BaseTeam) CalculateOptimalScore() {
 	total_score := 0.0
-	for _, lineup := range t.OptimalSlotting {
+	for day, lineup := range t.OptimalSlotting {
 		for _, player := range lineup {
-			total_score += player.AvgPoints
+			if t.LockMode.IsWeekly() && !t.Schedule.IsPlaying(t.Week, day, player.Team) {
+				continue
+			}
+			total_score += t.Schedule.GetPoints(player, t.Week, day)
 		}
 	}
 	t.Score = int(total_score)
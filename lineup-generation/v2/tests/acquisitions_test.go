@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"testing"
+	d "v2/data"
+	e "v2/exact"
+	p "v2/population"
+)
+
+func TestAcquisitionLimits(t *testing.T) {
+	limits := d.AcquisitionLimits{PerWeek: 4, PerDay: 2, Season: 3, WaiverDays: d.DefaultWaiverDays}
+	if limits.WeeklyLimit() != 3 {
+		t.Errorf("Expected the season budget to cap the week at 3, got %d", limits.WeeklyLimit())
+	}
+	if limits.Available(1, 1) != 1 || limits.Available(0, 2) != 0 || limits.Available(3, 0) != 0 {
+		t.Errorf("Unexpected available acquisitions %d %d %d", limits.Available(1, 1), limits.Available(0, 2), limits.Available(3, 0))
+	}
+	if !limits.Allows([]int{2, 0, 1}) || limits.Allows([]int{3, 0, 0}) || limits.Allows([]int{1, 1, 1, 1}) {
+		t.Error("Expected plans over the daily cap or the season budget to be rejected")
+	}
+
+	if d.DefaultAcquisitionLimits(6).WeeklyLimit() != 6 || !d.DefaultAcquisitionLimits(6).Allows([]int{6}) {
+		t.Error("Expected the default limits to allow one acquisition per day of the week with no daily cap")
+	}
+	if (d.AcquisitionLimits{PerWeek: -1, PerDay: d.NoLimit, Season: d.NoLimit, WaiverDays: d.DefaultWaiverDays}).Validate() == nil {
+		t.Error("Expected an error for a negative weekly limit")
+	}
+	if (d.AcquisitionLimits{PerWeek: 4, PerDay: d.NoLimit, Season: -2, WaiverDays: d.DefaultWaiverDays}).Validate() == nil {
+		t.Error("Expected an error for a negative season budget")
+	}
+}
+
+func TestExactSolverAcquisitionLimits(t *testing.T) {
+	bt := exactTestTeam()
+
+	// No daily pickups at all leaves the roster as it is
+	bt.Limits = d.AcquisitionLimits{PerWeek: 3, PerDay: 0, Season: d.NoLimit, WaiverDays: d.DefaultWaiverDays}
+	if result := e.InitSolver(bt, bt.Limits.WeeklyLimit(), e.DefaultNodeLimit).Solve(); result.Score != 18.0 {
+		t.Errorf("Expected 18 with no daily pickups, got %v", result.Score)
+	}
+
+	// The season budget caps the weekly limit
+	bt.Limits = d.AcquisitionLimits{PerWeek: 3, PerDay: d.NoLimit, Season: 1, WaiverDays: d.DefaultWaiverDays}
+	if result := e.InitSolver(bt, bt.Limits.WeeklyLimit(), e.DefaultNodeLimit).Solve(); result.Score != 54.0 || result.Acquisitions != 1 {
+		t.Errorf("Expected 54 with one acquisition, got %v with %d", result.Score, result.Acquisitions)
+	}
+
+	// One pickup a day still reaches the best plan, since its moves are on different days
+	bt.Limits = d.AcquisitionLimits{PerWeek: 3, PerDay: 1, Season: d.NoLimit, WaiverDays: d.DefaultWaiverDays}
+	result := e.InitSolver(bt, bt.Limits.WeeklyLimit(), e.DefaultNodeLimit).Solve()
+	if result.Score != 84.0 {
+		t.Errorf("Expected 84 with one pickup a day, got %v", result.Score)
+	}
+	for day, moves := range result.Moves {
+		if len(moves) > 1 {
+			t.Errorf("Day %d has %d moves with a daily cap of 1", day, len(moves))
+		}
+	}
+}
+
+func TestPopulationAcquisitionLimits(t *testing.T) {
+	bt := exactTestTeam()
+	bt.Limits = d.AcquisitionLimits{PerWeek: 1, PerDay: d.NoLimit, Season: d.NoLimit, WaiverDays: d.DefaultWaiverDays}
+
+	ev := p.InitPopulation(bt, 20)
+	for range 5 {
+		ev.Evolve(bt)
+	}
+
+	// Chromosomes that break the limits can't score, so they never beat the plan with no moves
+	for _, c := range ev.Population {
+		if !c.IsWithinLimits() && c.FitnessScore != 0 {
+			t.Errorf("Chromosome with %d acquisitions has fitness %d", c.TotalAcquisitions, c.FitnessScore)
+		}
+	}
+	ev.SortByFitness()
+	if best := ev.Population[ev.NumChromosomes-1]; !best.IsWithinLimits() {
+		t.Errorf("Fittest chromosome makes %d acquisitions with a limit of 1", best.TotalAcquisitions)
+	}
+}
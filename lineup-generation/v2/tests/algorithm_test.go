@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"math/rand"
+	"testing"
+	p "v2/population"
+	u "v2/utils"
+)
+
+func TestGetAlgorithm(t *testing.T) {
+	req := u.ReqBody{}
+	if req.GetAlgorithm() != u.DefaultAlgorithm() {
+		t.Errorf("Expected the defaults without settings, got %+v", req.GetAlgorithm())
+	}
+	if err := u.DefaultAlgorithm().Validate(); err != nil {
+		t.Errorf("Expected the defaults to be valid, got %v", err)
+	}
+
+	// Only the given settings change
+	islands, rate := 4, 0.35
+	req.Algorithm = &u.AlgorithmRequest{Islands: &islands, MutationRate: &rate}
+	algorithm := req.GetAlgorithm()
+	if algorithm.Islands != 4 || algorithm.MutationRate != 0.35 || algorithm.PopulationSize != u.DefaultAlgorithm().PopulationSize {
+		t.Errorf("Expected 4 islands and a 0.35 mutation rate over the defaults, got %+v", algorithm)
+	}
+}
+
+func TestAlgorithmValidate(t *testing.T) {
+	invalid := map[string]func(*u.Algorithm){
+		"population too small": func(a *u.Algorithm) { a.PopulationSize = 1 },
+		"population too large": func(a *u.Algorithm) { a.PopulationSize = u.MaxPopulationSize + 1 },
+		"no islands":           func(a *u.Algorithm) { a.Islands = 0 },
+		"negative generations": func(a *u.Algorithm) { a.Generations = -1 },
+		"mutation rate over 1": func(a *u.Algorithm) { a.MutationRate = 1.5 },
+		"negative pressure":    func(a *u.Algorithm) { a.SelectionPressure = -1 },
+		"offset over 1":        func(a *u.Algorithm) { a.SelectionOffset = 1.5 },
+		"no tournament groups": func(a *u.Algorithm) { a.TournamentGroups = 0 },
+		"too many groups":      func(a *u.Algorithm) { a.TournamentGroups = u.MaxTournamentGroups + 1 },
+		"empty tournament":     func(a *u.Algorithm) { a.TournamentSize = 0 },
+		"tournament too large": func(a *u.Algorithm) { a.TournamentSize = a.PopulationSize + 1 },
+		"elitism of everyone":  func(a *u.Algorithm) { a.Elitism = a.PopulationSize },
+		"unknown topology":     func(a *u.Algorithm) { a.Topology = "star" },
+		"migrants of everyone": func(a *u.Algorithm) { a.Migrants = a.PopulationSize },
+		"negative stall":       func(a *u.Algorithm) { a.StallGenerations = -1 },
+	}
+	for name, change := range invalid {
+		algorithm := u.DefaultAlgorithm()
+		change(&algorithm)
+		if algorithm.Validate() == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestEvolveKeepsElites(t *testing.T) {
+	bt := exactTestTeam()
+	config := u.DefaultAlgorithm()
+	config.Elitism = 3
+	ev := p.InitPopulationWithConfig(bt, config, 7)
+	if ev.NumChromosomes != config.PopulationSize {
+		t.Fatalf("Expected a population of %d, got %d", config.PopulationSize, ev.NumChromosomes)
+	}
+
+	// The fittest chromosomes are carried into the next generation unchanged
+	ev.SortByFitness()
+	elites := append([]*p.Chromosome{}, ev.Population[ev.NumChromosomes-config.Elitism:]...)
+	ev.Evolve(bt)
+	for _, elite := range elites {
+		found := false
+		for _, c := range ev.Population {
+			found = found || c == elite
+		}
+		if !found {
+			t.Errorf("Expected the elite with fitness %d to survive", elite.FitnessScore)
+		}
+	}
+}
+
+func TestTournamentPicksFittest(t *testing.T) {
+	bt := exactTestTeam()
+	config := u.DefaultAlgorithm()
+	config.TournamentGroups = 1
+	config.TournamentSize = config.PopulationSize
+	ev := p.InitPopulationWithConfig(bt, config, 11)
+
+	// Give every chromosome its own fitness, a single group the size of the population usually draws the fittest
+	for i, c := range ev.Population {
+		c.FitnessScore = i
+	}
+	rng := rand.New(rand.NewSource(5))
+	fittest := 0
+	for range 100 {
+		if ev.SelectParent(2, rng).FitnessScore == config.PopulationSize-1 {
+			fittest++
+		}
+	}
+	if fittest < 50 {
+		t.Errorf("Expected the fittest chromosome to win most tournaments, it won %d of 100", fittest)
+	}
+}
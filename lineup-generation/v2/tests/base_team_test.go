@@ -9,14 +9,14 @@ import (
 )
 
 func TestBTInitWithData(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/static/schedule25-26.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/static/schedule25-26.json")
 
 	// Test the InitBaseTeam function with mock data
 	week := 1
 	threshold := 30.0
 	roster := l.LoadFreeAgents("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/resources/mock_roster.json")
 	freeAgents := l.LoadFreeAgents("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/resources/mock_freeagents.json")
-	bt := team.InitBaseTeam(roster, freeAgents, week, threshold)
+	bt := team.InitBaseTeam(&schedule, roster, freeAgents, week, threshold, d.DefaultRosterTemplate(), d.SlotByRestrictiveness, d.LockDaily)
 
 	// Validate fields
 	BTFieldValidator(bt, t, "Anthony Edwards", "SG", 7, "MIN", threshold, "RosterMap")
@@ -27,12 +27,12 @@ func TestBTInitWithData(t *testing.T) {
 }
 
 func TestBTInitMock(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/static/schedule25-26.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/static/schedule25-26.json")
 
 	// Test the InitBaseTeamMock function
 	week := 1
 	threshold := 32.0
-	bt := team.InitBaseTeamMock(week, threshold)
+	bt := team.InitBaseTeamMock(&schedule, week, threshold)
 
 	// Validate fields
 	BTFieldValidator(bt, t, "Anthony Edwards", "SG", 7, "MIN", threshold, "RosterMap")
@@ -58,7 +58,7 @@ func TestBTInitMock(t *testing.T) {
 	fmt.Println("Streamable Players")
 	for _, player := range bt.StreamablePlayers {
 		fmt.Println(player.GetName(), player.GetAvgPoints(), player.GetTeam(), player.GetValidPositions())
-		for day := range d.ScheduleMap.GetWeekSchedule(week).TeamSchedules[player.GetTeam()] {
+		for day := range schedule.GetWeekSchedule(week).TeamSchedules[player.GetTeam()] {
 			fmt.Println("Playing day", day)
 		}
 	}
@@ -79,7 +79,7 @@ func TestBTPlayersToMap(t *testing.T) {
 }
 
 func TestBTOptimizeSlottingAndStreamablePlayers(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/static/schedule25-26.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/static/schedule25-26.json")
 
 	// Test the OptimizeSlotting function
 	week := 1
@@ -89,8 +89,9 @@ func TestBTOptimizeSlottingAndStreamablePlayers(t *testing.T) {
 	bt := &team.BaseTeam{
 		RosterMap: roster_map,
 		FreeAgents: free_agents,
+		Schedule: &schedule,
 	}
-	bt.OptimizeSlotting(week, threshold)
+	bt.OptimizeSlotting(week, threshold, d.SlotByRestrictiveness)
 
 	// Validate field
 	BTFieldValidator(bt, t, "Anthony Edwards", "SG", 7, "MIN", threshold, "OptimalSlotting")
@@ -98,7 +99,7 @@ func TestBTOptimizeSlottingAndStreamablePlayers(t *testing.T) {
 }
 
 func TestBTFindUnusedPositions(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/static/schedule25-26.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/Projects/cv/features/lineup-generation/v2/static/schedule25-26.json")
 
 	// Test the FindUnusedPositions function
 	week := 1
@@ -108,8 +109,9 @@ func TestBTFindUnusedPositions(t *testing.T) {
 	bt := &team.BaseTeam{
 		RosterMap: roster_map,
 		FreeAgents: free_agents,
+		Schedule: &schedule,
 	}
-	bt.OptimizeSlotting(week, threshold)
+	bt.OptimizeSlotting(week, threshold, d.SlotByRestrictiveness)
 	bt.FindUnusedPositions()
 
 	// Validate field
@@ -11,9 +11,9 @@ import (
 )
 
 func TestInitChromosome(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
 
-	bt := team.InitBaseTeamMock(1, 32.0)
+	bt := team.InitBaseTeamMock(&schedule, 1, 32.0)
 
 	c := p.InitChromosome(bt)
 
@@ -24,9 +24,9 @@ func TestInitChromosome(t *testing.T) {
 }
 
 func TestChromosomeInsertStreamablePlayers(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
 
-	bt := team.InitBaseTeamMock(1, 32.0)
+	bt := team.InitBaseTeamMock(&schedule, 1, 32.0)
 
 	c := p.InitChromosome(bt)
 
@@ -59,9 +59,9 @@ func TestChromosomeInsertStreamablePlayers(t *testing.T) {
 
 
 func TestInsertFreeAgent(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
 
-	bt := team.InitBaseTeamMock(1, 32.0)
+	bt := team.InitBaseTeamMock(&schedule, 1, 32.0)
 
 	c := p.InitChromosome(bt)
 
@@ -101,13 +101,13 @@ func TestInsertFreeAgent(t *testing.T) {
 }
 
 func TestPopulateChromosome(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
 
 	errors := 0
 	max_aquisitions := 0
 	for i := 0; i < 100; i++ {
 			
-		bt := team.InitBaseTeamMock(2, 34.0)
+		bt := team.InitBaseTeamMock(&schedule, 2, 34.0)
 		seed := time.Now().UnixNano() + int64(1)
 		rng := rand.New(rand.NewSource(seed))
 
@@ -201,9 +201,9 @@ func TestPopulateChromosome(t *testing.T) {
 }
 
 func TestChromosomeSlim(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/cv/stopz/v2/static/schedule.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/cv/stopz/v2/static/schedule.json")
 
-	bt := team.InitBaseTeamMock(2, 32.0)
+	bt := team.InitBaseTeamMock(&schedule, 2, 32.0)
 	seed := time.Now().UnixNano()
 	rng := rand.New(rand.NewSource(seed))
 
@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"testing"
+	d "v2/data"
+	e "v2/exact"
+	"v2/team"
+)
+
+// Small hand-built schedule for the exact solver tests
+func exactTestSchedule() d.SeasonSchedule {
+	return d.SeasonSchedule{Schedule: map[string]d.WeekSchedule{
+		"1": {
+			StartDate: "10/21/2025",
+			EndDate:   "10/24/2025",
+			GameSpan:  4,
+			TeamSchedules: map[string]map[string]bool{
+				"AAA": {"0": true, "1": true, "2": true, "3": true},
+				"BBB": {"0": true},
+				"CCC": {"1": true},
+				"DDD": {"1": true, "2": true, "3": true},
+				"EEE": {"0": true, "2": true},
+				"FFF": {"3": true},
+			},
+		},
+	}}
+}
+
+func exactTestTeam() *team.BaseTeam {
+	return exactTestTeamWithLock(d.LockDaily)
+}
+
+// Same team as exactTestTeam with the given lineup lock
+func exactTestTeamWithLock(lock d.LockMode) *team.BaseTeam {
+	guard := []string{"PG", "G", "UT1", "UT2", "UT3"}
+	roster := []d.Player{
+		{Name: "Star Center", AvgPoints: 50.0, Team: "AAA", ValidPositions: []string{"C", "UT1", "UT2", "UT3"}},
+		{Name: "Streamer A", AvgPoints: 10.0, Team: "BBB", ValidPositions: guard},
+		{Name: "Streamer B", AvgPoints: 8.0, Team: "CCC", ValidPositions: guard},
+	}
+	free_agents := []d.Player{
+		{Name: "Free Agent 1", AvgPoints: 12.0, Team: "DDD", ValidPositions: guard},
+		{Name: "Free Agent 2", AvgPoints: 9.0, Team: "EEE", ValidPositions: guard},
+		{Name: "Free Agent 3", AvgPoints: 20.0, Team: "FFF", ValidPositions: guard},
+		{Name: "Injured Agent", AvgPoints: 40.0, Team: "DDD", ValidPositions: guard, Injured: true},
+	}
+	schedule := exactTestSchedule()
+	return team.InitBaseTeam(&schedule, roster, free_agents, 1, 30.0, d.DefaultRosterTemplate(), d.SlotByRestrictiveness, lock)
+}
+
+func TestExactSolverFindsOptimum(t *testing.T) {
+	bt := exactTestTeam()
+
+	solver := e.InitSolver(bt, 2, e.DefaultNodeLimit)
+	result := solver.Solve()
+
+	// Streamer A on day 0 and Streamer B on day 1, then Free Agent 1 for days 1-3 and Free Agent 3 on day 3
+	if result.BaseScore != 18.0 {
+		t.Errorf("Expected base score 18, got %v", result.BaseScore)
+	}
+	if result.Score != 74.0 {
+		t.Errorf("Expected optimal score 74, got %v", result.Score)
+	}
+	if !result.Optimal || result.Gap != 0.0 || result.UpperBound != result.Score {
+		t.Errorf("Expected a proven optimum, got optimal %v gap %v bound %v", result.Optimal, result.Gap, result.UpperBound)
+	}
+	if result.Acquisitions != 2 {
+		t.Errorf("Expected 2 acquisitions, got %d", result.Acquisitions)
+	}
+
+	genes := solver.Slim(result)
+	if len(genes) != 4 {
+		t.Fatalf("Expected 4 genes, got %d", len(genes))
+	}
+	if len(genes[1].Additions) != 1 || genes[1].Additions[0].Name != "Free Agent 1" || genes[1].Removals[0].Name != "Streamer A" {
+		t.Errorf("Expected Free Agent 1 for Streamer A on day 1, got %v for %v", genes[1].Additions, genes[1].Removals)
+	}
+	if len(genes[3].Additions) != 1 || genes[3].Additions[0].Name != "Free Agent 3" || genes[3].Removals[0].Name != "Streamer B" {
+		t.Errorf("Expected Free Agent 3 for Streamer B on day 3, got %v for %v", genes[3].Additions, genes[3].Removals)
+	}
+
+	// The non-streamable player is added back to every day
+	for _, gene := range genes {
+		if gene.Roster["C"].Name != "Star Center" {
+			t.Errorf("Day %d: expected Star Center at C, got %v", gene.Day, gene.Roster["C"])
+		}
+	}
+	if genes[3].Roster["PG"].Name != "Free Agent 1" && genes[3].Roster["G"].Name != "Free Agent 1" {
+		t.Errorf("Free Agent 1 is not in the day 3 lineup: %v", genes[3].Roster)
+	}
+}
+
+func TestExactSolverRespectsAcquisitionLimit(t *testing.T) {
+	bt := exactTestTeam()
+
+	for max_acquisitions, expected := range []float64{18.0, 54.0, 74.0, 84.0} {
+		result := e.InitSolver(bt, max_acquisitions, e.DefaultNodeLimit).Solve()
+		if result.Score != expected {
+			t.Errorf("With %d acquisitions expected %v, got %v", max_acquisitions, expected, result.Score)
+		}
+		if result.Acquisitions > max_acquisitions {
+			t.Errorf("Used %d acquisitions with a limit of %d", result.Acquisitions, max_acquisitions)
+		}
+	}
+}
+
+func TestExactSolverNodeLimit(t *testing.T) {
+	bt := exactTestTeam()
+
+	// Stopping early still returns a feasible plan and a valid bound on the optimum
+	result := e.InitSolver(bt, 2, 1).Solve()
+	if result.Optimal {
+		t.Errorf("Expected the search to be cut off")
+	}
+	if result.Score < result.BaseScore || result.UpperBound < 74.0 {
+		t.Errorf("Expected base %v <= score %v and bound %v >= 74", result.BaseScore, result.Score, result.UpperBound)
+	}
+	if result.Gap < 0.0 || result.Gap > 1.0 {
+		t.Errorf("Gap should be between 0 and 1, got %v", result.Gap)
+	}
+}
@@ -9,19 +9,19 @@ import (
 )
 
 func TestGeneInit(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/cv/stopz/src/static/schedule.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/cv/stopz/src/static/schedule.json")
 
-	gene := p.InitGene(team.InitBaseTeamMock(1, 32.0), 0)
+	gene := p.InitGene(team.InitBaseTeamMock(&schedule, 1, 32.0), 0)
 	if gene.Day != 0 {
 		t.Errorf("Gene day is incorrect")
 	}
 }
 
 func TestGeneInsertStreamablePlayers(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/cv/stopz/src/static/schedule.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/cv/stopz/src/static/schedule.json")
 
 	// Test the InitGene function
-	bt := team.InitBaseTeamMock(1, 32.0)
+	bt := team.InitBaseTeamMock(&schedule, 1, 32.0)
 	day := 4
 	gene := p.InitGene(bt, day)
 	gene.InsertStreamablePlayers(bt)
@@ -62,16 +62,16 @@ func TestGeneInsertStreamablePlayers(t *testing.T) {
 }
 
 func TestGeneSlotPlayerDropBench(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
 
 	// Test the InitGene function
-	bt := team.InitBaseTeamMock(1, 34.0)
+	bt := team.InitBaseTeamMock(&schedule, 1, 34.0)
 	day := 0
 	gene := p.InitGene(bt, day)
 	gene.InsertStreamablePlayers(bt)
 
 	fmt.Println(gene.FreePositions)
-	gene.Print()
+	gene.Print(bt.Template)
 
 	// Test the SlotPlayer function
 	streamer1 := d.Player{
@@ -97,10 +97,10 @@ func TestGeneSlotPlayerDropBench(t *testing.T) {
 }
 
 func TestGeneSlotPlayerDropWorst(t *testing.T) {
-	d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
+	schedule := d.InitSchedule("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/static/schedule.json")
 
 	// Test the InitGene function
-	bt := team.InitBaseTeamMock(1, 32.0)
+	bt := team.InitBaseTeamMock(&schedule, 1, 32.0)
 	day := 4
 	gene := p.InitGene(bt, day)
 	gene.InsertStreamablePlayers(bt)
@@ -144,7 +144,7 @@ func TestGeneSlotPlayerDropWorst(t *testing.T) {
 	gene.RemoveStreamer(*player_to_drop)
 	gene.SlotPlayer(bt, streamer2)
 
-	gene.Print()
+	gene.Print(bt.Template)
 
 	// Make sure the players are in the right spot
 	if gene.Roster["G"].GetName() != "Test Player1" {
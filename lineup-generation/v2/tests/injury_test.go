@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"testing"
+	d "v2/data"
+	e "v2/exact"
+	"v2/team"
+)
+
+func TestPlayProbability(t *testing.T) {
+	return_day := 2
+	questionable := d.Player{Name: "Questionable", AvgPoints: 20.0, InjuryStatus: d.InjuryQuestionable, ReturnDay: &return_day}
+	if questionable.PlayProbability(0) != 0.5 || questionable.PlayProbability(2) != 1.0 {
+		t.Errorf("Expected 0.5 before the return day and 1 from then on, got %v and %v", questionable.PlayProbability(0), questionable.PlayProbability(2))
+	}
+
+	// A player only marked as injured is out for the whole week
+	injured := d.Player{Name: "Injured", Injured: true}
+	if injured.GetInjuryStatus() != d.InjuryOut || !injured.IsOutBetween(0, 7) {
+		t.Errorf("Expected an injured player without a status to be out, got %q", injured.GetInjuryStatus())
+	}
+
+	if err := d.ValidateInjuries([]d.Player{questionable, injured, {Name: "Hurt", InjuryStatus: "SORE"}}); err == nil {
+		t.Error("Expected an error for an unknown injury status")
+	}
+}
+
+func TestBTSlottingBringsPlayersBack(t *testing.T) {
+	schedule := exactTestSchedule()
+	return_day := 2
+	roster := []d.Player{
+		{Name: "Returning Center", AvgPoints: 50.0, Team: "AAA", ValidPositions: []string{"C"}, InjuryStatus: d.InjuryOut, ReturnDay: &return_day},
+		{Name: "Season Ending", AvgPoints: 50.0, Team: "AAA", ValidPositions: []string{"SF", "F"}, InjuryStatus: d.InjuryReserve},
+	}
+	bt := team.InitBaseTeam(&schedule, roster, []d.Player{}, 1, 30.0, d.DefaultRosterTemplate(), d.SlotByRestrictiveness, d.LockDaily)
+
+	for day, lineup := range bt.OptimalSlotting {
+		if returned := lineup["C"].Name == "Returning Center"; returned != (day >= return_day) {
+			t.Errorf("Day %d: expected the center back from day %d, got %v at C", day, return_day, lineup["C"])
+		}
+		for pos, player := range lineup {
+			if player.Name == "Season Ending" {
+				t.Errorf("Day %d: expected the player on IR to be left out, found at %s", day, pos)
+			}
+		}
+	}
+
+	// The open C slot goes to streamers until he's back
+	if !bt.UnusedPositions[0]["C"] || bt.UnusedPositions[2]["C"] {
+		t.Errorf("Expected C to be open only before day %d, got %v and %v", return_day, bt.UnusedPositions[0], bt.UnusedPositions[2])
+	}
+	if bt.Score != 100 {
+		t.Errorf("Expected 100 from his two games back, got %d", bt.Score)
+	}
+}
+
+func TestExactSolverWeighsInjuredFreeAgents(t *testing.T) {
+
+	// Free Agent 3 is doubtful for his one game, so Free Agent 2's two games are worth more as the second pickup
+	bt := exactTestTeam()
+	bt.FreeAgents[2].InjuryStatus = d.InjuryDoubtful
+	solver := e.InitSolver(bt, 2, e.DefaultNodeLimit)
+	result := solver.Solve()
+	if result.Score != 64.0 {
+		t.Errorf("Expected 64 without Free Agent 3, got %v", result.Score)
+	}
+	for _, gene := range solver.Slim(result) {
+		for _, player := range gene.Additions {
+			if player.Name == "Free Agent 3" || player.Name == "Injured Agent" {
+				t.Errorf("Expected %s not to be picked up, got him on day %d", player.Name, gene.Day)
+			}
+		}
+	}
+}
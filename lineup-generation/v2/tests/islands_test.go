@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	d "v2/data"
+	p "v2/population"
+	u "v2/utils"
+)
+
+// Function to find the fittest chromosome of an island
+func fittest(ev *p.EvolutionManager) *p.Chromosome {
+	ev.SortByFitness()
+	return ev.Population[ev.NumChromosomes-1]
+}
+
+func TestMigrateRing(t *testing.T) {
+	bt := exactTestTeam()
+	config := u.DefaultAlgorithm()
+	config.Islands, config.Migrants = 3, 1
+	im := p.InitIslandModel(bt, config, 11)
+
+	best := make([]*p.Chromosome, config.Islands)
+	for i, island := range im.Islands {
+		best[i] = fittest(island)
+	}
+	im.Migrate()
+
+	// Each island gets a copy of the previous island's best chromosome
+	for i, island := range im.Islands {
+		from := best[(i+config.Islands-1)%config.Islands]
+		found := false
+		for _, c := range island.Population {
+			if c == from {
+				t.Errorf("Island %d: expected a copy of the migrant, got the same chromosome", i)
+			}
+			found = found || c.FitnessScore == from.FitnessScore
+		}
+		if !found || island.NumChromosomes != config.PopulationSize {
+			t.Errorf("Island %d: expected the migrant with fitness %d in a population of %d", i, from.FitnessScore, config.PopulationSize)
+		}
+	}
+
+	// Copies don't share genes or streamers with the original
+	copied := best[0].Copy()
+	name := best[0].CurStreamers[0].Name
+	copied.Genes[0].Roster["UT3"] = d.Player{Name: "Someone Else"}
+	copied.CurStreamers[0].Name = "Someone Else"
+	if best[0].Genes[0].Roster["UT3"].Name == "Someone Else" || best[0].CurStreamers[0].Name != name {
+		t.Error("Expected the original to be left alone when the copy changes")
+	}
+}
+
+func TestMigrateFull(t *testing.T) {
+	bt := exactTestTeam()
+	config := u.DefaultAlgorithm()
+	config.Islands, config.Migrants, config.Topology = 3, 1, u.TopologyFull
+	im := p.InitIslandModel(bt, config, 5)
+
+	best := 0
+	for _, island := range im.Islands {
+		best = max(best, fittest(island).FitnessScore)
+	}
+	im.Migrate()
+
+	// Every island ends up with the best chromosome of the others, so the best one overall is on at least two islands
+	holders := 0
+	for _, island := range im.Islands {
+		if fittest(island).FitnessScore == best {
+			holders++
+		}
+	}
+	if holders < 2 {
+		t.Errorf("Expected the best fitness %d on at least two islands, got %d", best, holders)
+	}
+}
+
+func TestIslandModelRun(t *testing.T) {
+	bt := exactTestTeam()
+	config := u.DefaultAlgorithm()
+	config.Islands, config.Generations, config.MigrationInterval = 2, 4, 2
+	im := p.InitIslandModel(bt, config, 3)
+	if err := im.Run(context.Background(), bt); err != nil {
+		t.Fatalf("Expected the run to finish, got %v", err)
+	}
+	if ev := im.Combine(); ev.NumChromosomes != config.Islands*config.PopulationSize {
+		t.Errorf("Expected %d chromosomes combined, got %d", config.Islands*config.PopulationSize, ev.NumChromosomes)
+	}
+
+	// A cancelled run stops without evolving and keeps the populations
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	im = p.InitIslandModel(bt, config, 3)
+	before := im.Islands[0].Population[0]
+	if err := im.Run(ctx, bt); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the run to be cancelled, got %v", err)
+	}
+	if im.Islands[0].Population[0] != before {
+		t.Error("Expected no generations after the context is cancelled")
+	}
+}
+
+func TestIslandModelRunUntilDone(t *testing.T) {
+	bt := exactTestTeam()
+	config := u.DefaultAlgorithm()
+	config.Islands = 2
+	im := p.InitIslandModel(bt, config, 9)
+
+	// The islands keep evolving past their generations until the deadline, keeping the best plan seen
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := im.RunUntilDone(ctx, bt); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the run to stop at the deadline, got %v", err)
+	}
+	best := im.Best()
+	if best == nil || !best.IsWithinLimits() {
+		t.Fatal("Expected a best chromosome within the limits")
+	}
+	for _, island := range im.Islands {
+		for _, c := range island.Population {
+			if c.IsWithinLimits() && c.FitnessScore > best.FitnessScore {
+				t.Errorf("Expected the best chromosome to be at least as fit as %d, got %d", c.FitnessScore, best.FitnessScore)
+			}
+		}
+	}
+}
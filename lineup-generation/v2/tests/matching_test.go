@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"sort"
+	"testing"
+	d "v2/data"
+	"v2/team"
+)
+
+// Full day with more players than starting slots, guards and forwards compete for the flexible slots
+func slottingTestPlayers() []d.Player {
+	return []d.Player{
+		{Name: "Guard 1", AvgPoints: 48.0, ValidPositions: []string{"PG", "SG", "G"}},
+		{Name: "Guard 2", AvgPoints: 41.0, ValidPositions: []string{"PG", "G"}},
+		{Name: "Guard 3", AvgPoints: 36.0, ValidPositions: []string{"SG", "G"}},
+		{Name: "Guard 4", AvgPoints: 22.0, ValidPositions: []string{"PG", "SG", "G"}},
+		{Name: "Wing 1", AvgPoints: 39.0, ValidPositions: []string{"SG", "SF", "G", "F"}},
+		{Name: "Wing 2", AvgPoints: 27.0, ValidPositions: []string{"SF", "F"}},
+		{Name: "Forward 1", AvgPoints: 45.0, ValidPositions: []string{"SF", "PF", "F"}},
+		{Name: "Forward 2", AvgPoints: 30.0, ValidPositions: []string{"PF", "C", "F"}},
+		{Name: "Center 1", AvgPoints: 52.0, ValidPositions: []string{"C"}},
+		{Name: "Center 2", AvgPoints: 25.0, ValidPositions: []string{"PF", "C"}},
+		{Name: "Center 3", AvgPoints: 18.0, ValidPositions: []string{"C"}},
+		{Name: "Center 4", AvgPoints: 60.0, ValidPositions: []string{"C"}},
+		{Name: "Center 5", AvgPoints: 5.0, ValidPositions: []string{"C"}},
+	}
+}
+
+// Base team with no roster, only used for its template
+func slottingTestTeam() *team.BaseTeam {
+	template := d.DefaultRosterTemplate()
+	return &team.BaseTeam{Template: template, SlotWeights: template.Weights()}
+}
+
+// Function to slot players with the original backtracking search
+func fitSlottingPlayers(bt *team.BaseTeam, players []d.Player) *team.FitPlayersContext {
+	sorted := append([]d.Player{}, players...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i].ValidPositions) < len(sorted[j].ValidPositions)
+	})
+	ctx := &team.FitPlayersContext{BestLineup: make(map[string]d.Player), MaxScore: bt.CalculateMaxScore(sorted)}
+	bt.FitPlayers(sorted, make(map[string]d.Player), bt.Template.SlotOrder(), ctx, 0)
+	return ctx
+}
+
+func TestBTMatchPlayers(t *testing.T) {
+	bt := slottingTestTeam()
+	players := slottingTestPlayers()
+
+	// Every prefix of the day's players should reach the same restrictiveness score as the recursion
+	for n := 1; n <= len(players); n++ {
+		matched := bt.MatchPlayers(players[:n])
+		ctx := fitSlottingPlayers(bt, players[:n])
+		if score := bt.ScoreRoster(matched); score != ctx.TopScore {
+			t.Errorf("%d players: expected score %d, got %d", n, ctx.TopScore, score)
+		}
+		for pos, player := range matched {
+			if !bt.Template.Accepts(pos, player) {
+				t.Errorf("%d players: %s can't play %s", n, player.Name, pos)
+			}
+		}
+	}
+
+	// With every slot full the lowest scorers that still leave a legal lineup sit on the bench
+	for pos, player := range bt.MatchPlayers(players) {
+		if bt.Template.IsBench(pos) && player.Name != "Center 5" && player.Name != "Center 3" && player.Name != "Guard 4" {
+			t.Errorf("Expected only low scorers on the bench, found %s at %s", player.Name, pos)
+		}
+	}
+}
+
+func BenchmarkBTMatchPlayers(b *testing.B) {
+	bt := slottingTestTeam()
+	players := slottingTestPlayers()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bt.MatchPlayers(players)
+	}
+}
+
+func BenchmarkBTFitPlayers(b *testing.B) {
+	bt := slottingTestTeam()
+	players := slottingTestPlayers()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fitSlottingPlayers(bt, players)
+	}
+}
+
+func TestBTOptimizeSlottingByPoints(t *testing.T) {
+	schedule := d.SeasonSchedule{Schedule: map[string]d.WeekSchedule{
+		"1": {GameSpan: 1, TeamSchedules: map[string]map[string]bool{"AAA": {"0": true}}},
+	}}
+
+	// Every player plays on an overflow day with one more player than there are slots
+	roster := slottingTestPlayers()
+	roster = append(roster, d.Player{Name: "Guard 5", AvgPoints: 12.0, ValidPositions: []string{"PG", "G"}})
+	for i := range roster {
+		roster[i].Team = "AAA"
+	}
+
+	bt := team.InitBaseTeam(&schedule, roster, []d.Player{}, 1, 0.0, d.DefaultRosterTemplate(), d.SlotByPoints, d.LockDaily)
+	if bt.SlottingMode != d.SlotByPoints {
+		t.Errorf("Expected slotting mode %q, got %q", d.SlotByPoints, bt.SlottingMode)
+	}
+
+	// The ten best players start, the next three sit and the lowest scorer is left out
+	benched := map[string]bool{"Guard 4": true, "Center 3": true, "Guard 5": true}
+	started := 0.0
+	for pos, player := range bt.OptimalSlotting[0] {
+		if bt.Template.IsBench(pos) != benched[player.Name] {
+			t.Errorf("Unexpected %s at %s", player.Name, pos)
+		}
+		if player.Name == "Center 5" {
+			t.Errorf("Expected the lowest scorer to be left out, found at %s", pos)
+		}
+		if !bt.Template.IsBench(pos) {
+			started += player.AvgPoints
+		}
+	}
+	if started != 403.0 {
+		t.Errorf("Expected 403 points started, got %v", started)
+	}
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"testing"
+	d "v2/data"
+	e "v2/exact"
+	p "v2/population"
+)
+
+func TestSeasonScheduleProjectedPoints(t *testing.T) {
+	schedule := exactTestSchedule()
+	player := d.Player{Name: "Back To Back", AvgPoints: 12.0, Team: "DDD", Projections: map[string]float64{"2025-10-23": 0.0, "3": 15.0}}
+
+	// The day comes first, then the date, then the average
+	if points := schedule.GetPoints(player, 1, 3); points != 15.0 {
+		t.Errorf("Expected 15 points from the day's projection, got %v", points)
+	}
+	if points := schedule.GetPoints(player, 1, 2); points != 0.0 || !schedule.IsResting(player, 1, 2) {
+		t.Errorf("Expected a rest day on the second night of the back-to-back, got %v", points)
+	}
+	if points := schedule.GetPoints(player, 1, 1); points != 12.0 || schedule.IsResting(player, 1, 1) {
+		t.Errorf("Expected the average of 12 without a projection, got %v", points)
+	}
+	if points := schedule.WeeklyPoints(player, 1); points != 27.0 {
+		t.Errorf("Expected 27 points over the week, got %v", points)
+	}
+}
+
+func TestProjectionsScoreBaseTeam(t *testing.T) {
+	bt := exactTestTeam()
+	if bt.Score != 200 {
+		t.Fatalf("Expected the star to score 200 on his average, got %d", bt.Score)
+	}
+
+	// A minutes restriction on day 0 only counts for that game
+	bt = exactTestTeam()
+	bt.RosterMap["Star Center"] = d.Player{Name: "Star Center", AvgPoints: 50.0, Team: "AAA", ValidPositions: []string{"C", "UT1", "UT2", "UT3"}, Projections: map[string]float64{"0": 30.0}}
+	bt.OptimizeSlotting(bt.Week, 30.0, bt.SlottingMode)
+	bt.CalculateOptimalScore()
+	if bt.Score != 180 {
+		t.Errorf("Expected 180 with the minutes restriction, got %d", bt.Score)
+	}
+}
+
+func TestExactSolverSkipsRestDays(t *testing.T) {
+
+	// Free Agent 1 rests on days 2 and 3, so he's only worth his game on day 1
+	bt := exactTestTeam()
+	bt.FreeAgents[0].Projections = map[string]float64{"2": 0.0, "3": 0.0}
+
+	solver := e.InitSolver(bt, 2, e.DefaultNodeLimit)
+	result := solver.Solve()
+	if result.Score >= 74.0 {
+		t.Errorf("Expected less than the 74 he's worth on his average, got %v", result.Score)
+	}
+	for _, gene := range solver.Slim(result)[2:] {
+		for _, player := range gene.Roster {
+			if player.Name == "Free Agent 1" {
+				t.Errorf("Free Agent 1 starts on day %d while resting", gene.Day)
+			}
+		}
+	}
+}
+
+func TestPopulationUsesProjections(t *testing.T) {
+	bt := exactTestTeam()
+	bt.FreeAgents[0].Projections = map[string]float64{"2": 0.0, "3": 0.0}
+
+	ev := p.InitPopulation(bt, 20)
+	for range 3 {
+		ev.Evolve(bt)
+	}
+
+	// Streamers projected to rest sit on the bench and every game scores its projection
+	for _, c := range ev.Population {
+		fitness := 0.0
+		for _, gene := range c.Genes {
+			for _, player := range gene.Roster {
+				if player.Name == "Free Agent 1" && gene.Day >= 2 {
+					t.Errorf("Free Agent 1 starts on day %d while resting", gene.Day)
+				}
+				if bt.Schedule.IsPlaying(bt.Week, gene.Day, player.Team) {
+					fitness += bt.Schedule.GetPoints(player, bt.Week, gene.Day)
+				}
+			}
+		}
+		if c.IsWithinLimits() && c.FitnessScore != int(fitness) {
+			t.Errorf("Expected fitness %d from the projections, got %d", int(fitness), c.FitnessScore)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"testing"
+	e "v2/exact"
+	p "v2/population"
+	u "v2/utils"
+)
+
+func TestExactSolverReplansFromCurrentDay(t *testing.T) {
+	bt := exactTestTeam()
+	bt.CurrentDay = 2
+
+	solver := e.InitSolver(bt, 2, e.DefaultNodeLimit)
+	result := solver.Solve()
+
+	// Both streamers are done playing, so Free Agent 1 on days 2-3 and Free Agent 3 on day 3 are all that's left
+	if result.BaseScore != 0.0 || result.Score != 44.0 {
+		t.Errorf("Expected 44 points over a base of 0, got %v over %v", result.Score, result.BaseScore)
+	}
+	for day, moves := range result.Moves[:2] {
+		if len(moves) > 0 {
+			t.Errorf("Expected no moves on day %d before the current day, got %v", day, moves)
+		}
+	}
+}
+
+func TestPopulationReplansFromCurrentDay(t *testing.T) {
+	bt := exactTestTeam()
+	bt.CurrentDay = 2
+
+	ev := p.InitPopulation(bt, 20)
+	for range 3 {
+		ev.Evolve(bt)
+	}
+
+	for _, c := range ev.Population {
+
+		// Nothing changes on the days that are over and they don't score
+		fitness := 0.0
+		for _, gene := range c.Genes {
+			if gene.Day < bt.CurrentDay {
+				if gene.Acquisitions > 0 {
+					t.Errorf("Day %d has %d acquisitions before the current day", gene.Day, gene.Acquisitions)
+				}
+				continue
+			}
+			for _, player := range gene.Roster {
+				if bt.Schedule.IsPlaying(bt.Week, gene.Day, player.Team) {
+					fitness += player.AvgPoints
+				}
+			}
+		}
+		if c.IsWithinLimits() && c.FitnessScore != int(fitness) {
+			t.Errorf("Expected fitness %d from the remaining days, got %d", int(fitness), c.FitnessScore)
+		}
+	}
+}
+
+func TestAcquisitionsUsedThisWeek(t *testing.T) {
+	per_week := 3
+	request := u.ReqBody{MaxAcquisitionsPerWeek: &per_week, AcquisitionsUsed: 2}
+	limits := request.GetAcquisitionLimits(7)
+	if limits.WeeklyLimit() != 1 {
+		t.Errorf("Expected 1 acquisition left this week, got %d", limits.WeeklyLimit())
+	}
+	if limits.Allows([]int{0, 0, 2}) {
+		t.Error("Expected 2 more acquisitions to break the weekly limit")
+	}
+
+	request.AcquisitionsUsed = -1
+	if err := request.GetAcquisitionLimits(7).Validate(); err == nil {
+		t.Error("Expected an error for negative acquisitions used")
+	}
+}
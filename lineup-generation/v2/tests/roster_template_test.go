@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"testing"
+	d "v2/data"
+	p "v2/population"
+	"v2/team"
+	u "v2/utils"
+)
+
+// Template for a league with 2 UT spots that take anyone and 4 bench spots
+func customRosterTemplate() d.RosterTemplate {
+	any_position := []string{"PG", "SG", "SF", "PF", "C"}
+	return d.RosterTemplate{
+		Slots: []d.Slot{
+			{Name: "PG", Positions: []string{"PG"}, Weight: 5},
+			{Name: "C", Positions: []string{"C"}, Weight: 4},
+			{Name: "UT1", Positions: any_position, Weight: 2},
+			{Name: "UT2", Positions: any_position, Weight: 2},
+		},
+		Bench: 4,
+		IR:    1,
+	}
+}
+
+func TestBTCustomRosterTemplate(t *testing.T) {
+	schedule := d.SeasonSchedule{Schedule: map[string]d.WeekSchedule{
+		"1": {
+			StartDate:     "10/21/2025",
+			EndDate:       "10/22/2025",
+			GameSpan:      2,
+			TeamSchedules: map[string]map[string]bool{"AAA": {"0": true}, "BBB": {"0": true, "1": true}},
+		},
+	}}
+
+	roster := []d.Player{
+		{Name: "Point Guard", AvgPoints: 50.0, Team: "AAA", ValidPositions: []string{"PG", "G"}},
+		{Name: "Center", AvgPoints: 40.0, Team: "BBB", ValidPositions: []string{"C"}},
+		{Name: "Small Forward", AvgPoints: 35.0, Team: "AAA", ValidPositions: []string{"SF", "F"}},
+		{Name: "Streaming Forward", AvgPoints: 10.0, Team: "BBB", ValidPositions: []string{"SF", "F"}},
+	}
+	bt := team.InitBaseTeam(&schedule, roster, []d.Player{}, 1, 30.0, customRosterTemplate(), d.SlotByRestrictiveness, d.LockDaily)
+
+	// Day 0 uses the PG, C and one UT slot, leaving the other UT slot for streamers
+	if bt.OptimalSlotting[0]["PG"].Name != "Point Guard" || bt.OptimalSlotting[0]["C"].Name != "Center" {
+		t.Errorf("Unexpected slotting for day 0: %v", bt.OptimalSlotting[0])
+	}
+	if len(bt.UnusedPositions[0]) != 1 {
+		t.Errorf("Expected one unused position on day 0, got %v", bt.UnusedPositions[0])
+	}
+	for _, pos := range []string{"G", "F", "UT3"} {
+		if _, ok := bt.OptimalSlotting[0][pos]; ok {
+			t.Errorf("Position %s is not in the template but was slotted", pos)
+		}
+	}
+
+	// The streamer can only go in a UT slot since the template has no SF slot
+	gene := p.InitGene(bt, 1)
+	gene.InsertStreamablePlayers(bt)
+	if pos := gene.GetPosOfPlayer(roster[3]); pos != "UT1" {
+		t.Errorf("Expected the streamer at UT1 on day 1, got %s", pos)
+	}
+}
+
+func TestRequestRefusesIRSlots(t *testing.T) {
+	// The default template has no IR slots in v2, so a request without one can be planned
+	req := u.ReqBody{}
+	if req.GetRosterTemplate().IR != 0 || req.ValidateRosterTemplate() != nil {
+		t.Errorf("Expected the default template without IR slots to be accepted, got %+v", req.GetRosterTemplate())
+	}
+
+	// IR slots would be ignored by the planner, so they're refused
+	template := customRosterTemplate()
+	req.RosterTemplate = &template
+	if req.ValidateRosterTemplate() == nil {
+		t.Error("Expected a template with an IR slot to be refused")
+	}
+	template.IR = 0
+	if err := req.ValidateRosterTemplate(); err != nil {
+		t.Errorf("Expected the template without IR slots to be accepted, got %v", err)
+	}
+}
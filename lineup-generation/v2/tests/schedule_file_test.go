@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"testing"
+	d "v2/data"
+	u "v2/utils"
+)
+
+func TestParseSeasonScheduleLayouts(t *testing.T) {
+	layouts := map[string]string{
+		"versioned": `{"version": 1, "season": "2025-26", "weeks": {"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {
+			"OKC": [{"day": 0, "opponent": "HOU", "home": true, "tipoff": "2025-10-21T23:30:00Z"}, {"day": 2, "opponent": "LAL", "home": false}],
+			"HOU": [{"day": 0, "opponent": "OKC", "home": false, "tipoff": "2025-10-21T23:30:00Z"}]
+		}}}}`,
+		"legacy v2": `{"schedule": {"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {
+			"OKC": {"0": true, "2": true},
+			"HOU": {"0": true}
+		}}}}`,
+		"legacy v3": `{"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {
+			"OKC": [0, 2],
+			"HOU": [0]
+		}}}`,
+	}
+
+	for name, data := range layouts {
+		schedule, err := d.ParseSeasonSchedule([]byte(data))
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", name, err)
+		}
+		if schedule.GetGameSpan(1) != 6 {
+			t.Errorf("%s: expected a game span of 6, got %d", name, schedule.GetGameSpan(1))
+		}
+		for day, expected := range []bool{true, false, true, false, false, false} {
+			if schedule.IsPlaying(1, day, "OKC") != expected {
+				t.Errorf("%s: expected OKC playing on day %d to be %v", name, day, expected)
+			}
+		}
+		if !schedule.IsPlaying(1, 0, "HOU") || schedule.IsPlaying(1, 2, "HOU") {
+			t.Errorf("%s: unexpected HOU schedule %v", name, schedule.Schedule["1"].TeamSchedules["HOU"])
+		}
+	}
+
+	// Only the versioned format has the game details
+	schedule, _ := d.ParseSeasonSchedule([]byte(layouts["versioned"]))
+	if games := schedule.GetGames(1, "OKC"); len(games) != 2 || games[0].Opponent != "HOU" || games[0].Home == nil || !*games[0].Home || games[0].TipOff != "2025-10-21T23:30:00Z" {
+		t.Errorf("Unexpected OKC games %+v", games)
+	}
+
+	if _, err := d.ParseSeasonSchedule([]byte(`{"version": 99, "weeks": {}}`)); err == nil {
+		t.Error("Expected an error for an unknown schedule version")
+	}
+}
+
+func TestSeasonScheduleDates(t *testing.T) {
+	schedule, err := d.ParseSeasonSchedule([]byte(`{"version": 1, "weeks": {
+		"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {"OKC": [{"day": 0}]}},
+		"2": {"startDate": "10/27/2025", "endDate": "11/02/2025", "gameSpan": 7, "games": {"OKC": [{"day": 1}]}}
+	}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if date := schedule.GetISODate(2, 3); date != "2025-10-30" {
+		t.Errorf("Expected day 3 of week 2 on 2025-10-30, got %s", date)
+	}
+	if date := schedule.GetISODate(5, 0); date != "" {
+		t.Errorf("Expected no date for a missing week, got %s", date)
+	}
+
+	date, _ := d.ParseISODate("2025-10-26")
+	if week, day, err := schedule.FindWeek(date); err != nil || week != 1 || day != 5 {
+		t.Errorf("Expected 2025-10-26 on day 5 of week 1, got day %d of week %d %v", day, week, err)
+	}
+	date, _ = d.ParseISODate("2025-11-03")
+	if _, _, err := schedule.FindWeek(date); err == nil {
+		t.Error("Expected an error for a date outside the season")
+	}
+
+	// Every day of the response gets its date
+	response := u.Response{Week: 2, Lineup: []u.SlimGene{{Day: 0}, {Day: 6}}}
+	response.AddDates(&schedule)
+	if response.Lineup[0].Date != "2025-10-27" || response.Lineup[1].Date != "2025-11-02" {
+		t.Errorf("Unexpected dates %s and %s", response.Lineup[0].Date, response.Lineup[1].Date)
+	}
+}
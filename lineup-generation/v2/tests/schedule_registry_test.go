@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	d "v2/data"
+)
+
+func TestScheduleRegistry(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data string, mod_time time.Time) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		os.Chtimes(path, mod_time, mod_time)
+	}
+
+	// The older season has OKC listed twice on a day in week 2
+	loaded := time.Now().Add(-time.Hour)
+	write("schedule24-25.json", `{"version": 1, "season": "2024-25", "weeks": {
+		"1": {"startDate": "10/22/2024", "endDate": "10/23/2024", "gameSpan": 2, "games": {"OKC": [{"day": 0}]}},
+		"2": {"startDate": "10/24/2024", "endDate": "10/25/2024", "gameSpan": 2, "games": {"OKC": [{"day": 1}, {"day": 1}]}}
+	}}`, loaded)
+	write("schedule25-26.json", `{"version": 1, "season": "2025-26", "weeks": {
+		"1": {"startDate": "10/21/2025", "endDate": "10/22/2025", "gameSpan": 2, "games": {"OKC": [{"day": 1}]}}
+	}}`, loaded)
+
+	registry, err := d.NewScheduleRegistry(dir)
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	if registry.DefaultSeason() != "2025-26" || !registry.HasSeason("2024-25") {
+		t.Fatalf("Expected both seasons with 2025-26 as the default, got %v", registry.Seasons())
+	}
+
+	// Only the weeks being planned have to be valid
+	if _, err := registry.Get("2024-25", d.LoadOptions{RejectInvalid: true, Weeks: []int{1}}); err != nil {
+		t.Errorf("Expected week 1 of 2024-25 to be usable, got %v", err)
+	}
+	if _, err := registry.Get("2024-25", d.LoadOptions{RejectInvalid: true, Weeks: []int{2}}); err == nil {
+		t.Error("Expected week 2 of 2024-25 to be refused")
+	}
+
+	// A changed file is picked up on reload
+	write("schedule25-26.json", `{"version": 1, "season": "2025-26", "weeks": {
+		"1": {"startDate": "10/21/2025", "endDate": "10/22/2025", "gameSpan": 2, "games": {"OKC": [{"day": 0}]}}
+	}}`, loaded.Add(time.Minute))
+	registry.Reload()
+	schedule, err := registry.Get("", d.LoadOptions{})
+	if err != nil || !schedule.IsPlaying(1, 0, "OKC") || schedule.IsPlaying(1, 1, "OKC") {
+		t.Errorf("Expected the reloaded schedule with OKC on day 0, got %v %v", schedule.Schedule, err)
+	}
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	d "v2/data"
+)
+
+func TestValidateLegacySchedule(t *testing.T) {
+	// Week 1 says it's five days long but runs Tuesday to Sunday, and ATL plays on the sixth day
+	data := `{"schedule": {
+		"1": {"startDate": "10/22/2024", "endDate": "10/27/2024", "gameSpan": 5, "games": {
+			"ATL": {"0": true, "5": true},
+			"BOS": {"1": true}
+		}},
+		"2": {"startDate": "10/28/2024", "endDate": "11/03/2024", "gameSpan": 7, "games": {
+			"ATL": {"0": true}
+		}}
+	}}`
+
+	errs := d.ValidateSchedule([]byte(data))
+	expected := []d.ScheduleErrorKind{d.GameSpanMismatch, d.DayOutOfRange, d.MissingTeam}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d:\n%v", len(expected), len(errs), errs)
+	}
+	for i, kind := range expected {
+		if errs[i].Kind != kind {
+			t.Errorf("Error %d: expected %s, got %v", i, kind, errs[i])
+		}
+	}
+	if errs[1].Line != 3 || errs[1].Team != "ATL" || errs[1].Day != 5 {
+		t.Errorf("Expected ATL's day 5 on line 3, got %+v", errs[1])
+	}
+	if errs[2].Week != "2" || errs[2].Team != "BOS" || !errs[2].Warning {
+		t.Errorf("Expected a warning for BOS missing from week 2, got %+v", errs[2])
+	}
+
+	// Weeks without problems have nothing to report
+	if week_errs := errs.ForWeeks(3); week_errs.Err() != nil {
+		t.Errorf("Expected no errors in week 3, got %v", week_errs)
+	}
+}
+
+func TestValidateDuplicateDays(t *testing.T) {
+	// The legacy layout keys days by number, so a day listed twice is a key listed twice
+	errs := d.ValidateSchedule([]byte(`{"schedule": {"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {
+		"OKC": {"0": true, "0": false}
+	}}}}`))
+	if len(errs) != 1 || errs[0].Kind != d.DuplicateKey || errs[0].Line != 2 || errs[0].Week != "1" || errs[0].Warning {
+		t.Errorf("Expected a duplicate key on line 2 of week 1, got %v", errs)
+	}
+}
+
+func TestValidateStaticSchedules(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "..", "static", d.ScheduleFilePattern))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("Expected schedule files in static, got %v %v", paths, err)
+	}
+
+	// The server refuses invalid weeks, so every schedule that ships has to pass
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if err := d.ValidateSchedule(data).Err(); err != nil {
+			t.Errorf("%s:\n%v", path, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+	p "v2/population"
+	u "v2/utils"
+)
+
+func TestRecordStats(t *testing.T) {
+	bt := exactTestTeam()
+	ev := p.InitPopulationWithSeed(bt, 20, 13)
+	for range 3 {
+		ev.Evolve(bt)
+	}
+
+	// The first population and each generation after it get their stats
+	if len(ev.Stats) != 4 {
+		t.Fatalf("Expected stats for 4 generations, got %d", len(ev.Stats))
+	}
+	best, infeasible := ev.Population[0].FitnessScore, 0
+	for _, c := range ev.Population {
+		best = max(best, c.FitnessScore)
+		if !c.IsWithinLimits() {
+			infeasible++
+		}
+	}
+	for i, stats := range ev.Stats {
+		if stats.Generation != i || float64(stats.Best) < stats.Mean || stats.Mean < float64(stats.Worst) {
+			t.Errorf("Generation %d: expected best >= mean >= worst, got %+v", i, stats)
+		}
+		if stats.Diversity <= 0 || stats.Diversity > 1 {
+			t.Errorf("Generation %d: expected a diversity between 0 and 1, got %v", i, stats.Diversity)
+		}
+	}
+	if last := ev.Stats[3]; last.Best != best || last.Infeasible != infeasible {
+		t.Errorf("Expected the last stats to match the population's best %d and %d infeasible, got %+v", best, infeasible, last)
+	}
+}
+
+func TestIslandModelStopsWhenStalled(t *testing.T) {
+	bt := exactTestTeam()
+	config := u.DefaultAlgorithm()
+	config.Islands, config.Generations, config.StallGenerations = 2, u.MaxGenerations, 3
+	im := p.InitIslandModel(bt, config, 17)
+
+	// The small week is solved quickly, so every island stops long before its generations are up
+	if err := im.Run(context.Background(), bt); err != nil {
+		t.Fatalf("Expected the run to converge, got %v", err)
+	}
+	if !im.IsStalled() {
+		t.Error("Expected every island to have stalled")
+	}
+	for i, stats := range im.Stats() {
+		if len(stats) > u.MaxGenerations {
+			t.Errorf("Island %d: expected an early stop, ran %d generations", i, len(stats)-1)
+		}
+	}
+
+	// A time budget isn't used up once the islands have stalled
+	im = p.InitIslandModel(bt, config, 17)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := im.RunUntilDone(ctx, bt); err != nil || time.Since(start) > 10*time.Second {
+		t.Errorf("Expected the run to stop once stalled, got %v after %v", err, time.Since(start))
+	}
+}
@@ -16,4 +16,4 @@ func printMemUsage() {
 
 func bToMb(b uint64) uint64 {
 	return b / 1024 / 1024
-}
\ No newline at end of file
+}
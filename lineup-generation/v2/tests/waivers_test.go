@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"testing"
+	d "v2/data"
+	e "v2/exact"
+	p "v2/population"
+)
+
+func TestDecrementDroppedPlayers(t *testing.T) {
+	c := &p.Chromosome{DroppedPlayers: map[string]d.DroppedPlayer{
+		"Player A": {Player: d.Player{Name: "Player A"}, Countdown: 2},
+		"Player B": {Player: d.Player{Name: "Player B"}, Countdown: 1},
+	}}
+
+	// The countdown is stored back in the map and players that cleared waivers are removed
+	c.DecrementDroppedPlayers()
+	if c.DroppedPlayers["Player A"].Countdown != 1 {
+		t.Errorf("Expected Player A to have 1 day left, got %d", c.DroppedPlayers["Player A"].Countdown)
+	}
+	if _, ok := c.DroppedPlayers["Player B"]; ok {
+		t.Errorf("Expected Player B to have cleared waivers")
+	}
+
+	c.DecrementDroppedPlayers()
+	if len(c.DroppedPlayers) != 0 {
+		t.Errorf("Expected every player to have cleared waivers, got %v", c.DroppedPlayers)
+	}
+}
+
+func TestExactSolverWaiverUntilDay(t *testing.T) {
+	// Free Agent 3 only plays on day 3, so clearing waivers that day still lets him be picked up
+	bt := exactTestTeam()
+	bt.FreeAgents[2].WaiverUntilDay = 3
+	if result := e.InitSolver(bt, 2, e.DefaultNodeLimit).Solve(); result.Score != 74.0 {
+		t.Errorf("Expected 74 when Free Agent 3 clears waivers on day 3, got %v", result.Score)
+	}
+
+	// On waivers for the whole week he can't be picked up at all
+	bt = exactTestTeam()
+	bt.FreeAgents[2].WaiverUntilDay = 4
+	solver := e.InitSolver(bt, 2, e.DefaultNodeLimit)
+	result := solver.Solve()
+	if result.Score >= 74.0 {
+		t.Errorf("Expected less than 74 without Free Agent 3, got %v", result.Score)
+	}
+	for _, gene := range solver.Slim(result) {
+		for _, player := range gene.Additions {
+			if player.Name == "Free Agent 3" {
+				t.Errorf("Free Agent 3 was picked up on day %d while on waivers", gene.Day)
+			}
+		}
+	}
+}
+
+func TestPopulationSkipsPlayersOnWaivers(t *testing.T) {
+	bt := exactTestTeam()
+	for i := range bt.FreeAgents {
+		bt.FreeAgents[i].WaiverUntilDay = 4
+	}
+
+	// Every free agent is on waivers for the whole week, so no chromosome can pick anyone up
+	ev := p.InitPopulation(bt, 20)
+	for range 3 {
+		ev.Evolve(bt)
+	}
+	for _, c := range ev.Population {
+		if c.TotalAcquisitions != 0 {
+			t.Errorf("Expected no acquisitions, got %d", c.TotalAcquisitions)
+		}
+	}
+}
+
+// Function to check that every player dropped on a day still has the waiver days left that a countdown started that day
+// and decremented at the end of each day after would leave
+func checkWaiverCountdowns(t *testing.T, name string, c *p.Chromosome) {
+	expected := make(map[string]int)
+	for _, gene := range c.Genes {
+		for _, player := range gene.DroppedPlayers {
+			expected[player.Name] = c.Limits.WaiverDays - (len(c.Genes) - gene.Day)
+		}
+	}
+	for player, countdown := range expected {
+		if c.DroppedPlayers[player].Countdown != countdown {
+			t.Errorf("%s: expected %s to have %d waiver days left, got %d", name, player, countdown, c.DroppedPlayers[player].Countdown)
+		}
+	}
+}
+
+func TestCrossoverMatchesPopulateWaivers(t *testing.T) {
+	bt := exactTestTeam()
+	bt.Limits.WaiverDays = 10
+
+	// Long enough waivers that no one clears them this week, so each countdown shows how many days it ran
+	ev := p.InitPopulationWithSeed(bt, 20, 3)
+	drops := 0
+	for i, c := range ev.Population {
+		checkWaiverCountdowns(t, "populate", c)
+		child := ev.Crossover(bt, c, ev.Population[(i+1)%ev.NumChromosomes], ev.Rng)
+		checkWaiverCountdowns(t, "crossover", child)
+		drops += len(child.DroppedPlayers)
+	}
+	if drops == 0 {
+		t.Fatal("Expected crossover to drop someone")
+	}
+}
@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Bounds on the genetic algorithm settings a request can ask for, they keep a single request from tying up the server
+const (
+	MinPopulationSize    = 2
+	MaxPopulationSize    = 200
+	MaxIslands           = 16
+	MaxGenerations       = 200
+	MaxSelectionPressure = 5.0
+	MaxSelectionOffset   = 1.0
+	MaxTournamentGroups  = 10
+)
+
+// Which islands trade chromosomes when they migrate
+type Topology string
+
+const (
+	// Each island sends its best chromosomes to the next one
+	TopologyRing Topology = "ring"
+	// Each island takes the best chromosomes sent by all of the others
+	TopologyFull Topology = "full"
+)
+
+// Function to check that a topology is known
+func (t Topology) Validate() error {
+	switch t {
+	case TopologyRing, TopologyFull:
+		return nil
+	default:
+		return fmt.Errorf("unknown topology %q", string(t))
+	}
+}
+
+// Struct for the genetic algorithm settings a request can change, the default is used for each one that isn't given
+type AlgorithmRequest struct {
+	PopulationSize    *int     `json:"population_size"`
+	Islands           *int     `json:"islands"`
+	Generations       *int     `json:"generations"`
+	MutationRate      *float64 `json:"mutation_rate"`
+	SelectionPressure *float64 `json:"selection_pressure"`
+	SelectionOffset   *float64 `json:"selection_offset"`
+	TournamentGroups  *int     `json:"tournament_groups"`
+	TournamentSize    *int     `json:"tournament_size"`
+	Elitism           *int     `json:"elitism"`
+	Topology          Topology `json:"topology"`
+	MigrationInterval *int     `json:"migration_interval"`
+	Migrants          *int     `json:"migrants"`
+	StallGenerations  *int     `json:"stall_generations"`
+}
+
+// Struct for the settings the genetic algorithm runs with. Each island evolves a population of [PopulationSize] for
+// [Generations] generations, and every [MigrationInterval] generations the [Migrants] fittest chromosomes of each island
+// are copied to the islands next to it in the [Topology]. One parent is picked by roulette, where the [SelectionPressure]
+// is the exponent on a chromosome's fitness rank, higher favors the fittest more, and the [SelectionOffset] is added to
+// every weight so the least fit can still be picked. The other is the fittest of one of [TournamentGroups] groups of
+// [TournamentSize] chromosomes drawn at random, bigger groups favor the fittest more. The [Elitism] fittest
+// chromosomes are carried into each generation unchanged. An island stops once its best plan hasn't improved for
+// [StallGenerations] generations, 0 never stops early
+type Algorithm struct {
+	PopulationSize    int
+	Islands           int
+	Generations       int
+	MutationRate      float64
+	SelectionPressure float64
+	SelectionOffset   float64
+	TournamentGroups  int
+	TournamentSize    int
+	Elitism           int
+	Topology          Topology
+	MigrationInterval int
+	Migrants          int
+	StallGenerations  int
+}
+
+// Function to get the settings used when the request has none, with one island for each CPU. The island count is sent
+// back with the seed, so a plan can be reproduced on a server with a different number of CPUs
+func DefaultAlgorithm() Algorithm {
+	return Algorithm{
+		PopulationSize:    20,
+		Islands:           min(runtime.NumCPU(), MaxIslands),
+		Generations:       20,
+		MutationRate:      0.20,
+		SelectionPressure: 1.5,
+		SelectionOffset:   0.02,
+		TournamentGroups:  3,
+		TournamentSize:    5,
+		Elitism:           1,
+		Topology:          TopologyRing,
+		MigrationInterval: 5,
+		Migrants:          2,
+	}
+}
+
+// Function to check that every setting is within its bounds
+func (a Algorithm) Validate() error {
+	if a.PopulationSize < MinPopulationSize || a.PopulationSize > MaxPopulationSize {
+		return fmt.Errorf("population size must be between %d and %d, got %d", MinPopulationSize, MaxPopulationSize, a.PopulationSize)
+	}
+	if a.Islands < 1 || a.Islands > MaxIslands {
+		return fmt.Errorf("islands must be between 1 and %d, got %d", MaxIslands, a.Islands)
+	}
+	if a.Generations < 0 || a.Generations > MaxGenerations {
+		return fmt.Errorf("generations must be between 0 and %d, got %d", MaxGenerations, a.Generations)
+	}
+	if a.MutationRate < 0 || a.MutationRate > 1 {
+		return fmt.Errorf("mutation rate must be between 0 and 1, got %v", a.MutationRate)
+	}
+	if a.SelectionPressure < 0 || a.SelectionPressure > MaxSelectionPressure {
+		return fmt.Errorf("selection pressure must be between 0 and %v, got %v", MaxSelectionPressure, a.SelectionPressure)
+	}
+	if a.SelectionOffset < 0 || a.SelectionOffset > MaxSelectionOffset {
+		return fmt.Errorf("selection offset must be between 0 and %v, got %v", MaxSelectionOffset, a.SelectionOffset)
+	}
+	if a.TournamentGroups < 1 || a.TournamentGroups > MaxTournamentGroups {
+		return fmt.Errorf("tournament groups must be between 1 and %d, got %d", MaxTournamentGroups, a.TournamentGroups)
+	}
+	if a.TournamentSize < 1 || a.TournamentSize > a.PopulationSize {
+		return fmt.Errorf("tournament size must be between 1 and the population size, got %d", a.TournamentSize)
+	}
+	if a.Elitism < 0 || a.Elitism >= a.PopulationSize {
+		return fmt.Errorf("elitism must be between 0 and one less than the population size, got %d", a.Elitism)
+	}
+	if err := a.Topology.Validate(); err != nil {
+		return err
+	}
+	if a.MigrationInterval < 0 || a.MigrationInterval > MaxGenerations {
+		return fmt.Errorf("migration interval must be between 0 and %d, got %d", MaxGenerations, a.MigrationInterval)
+	}
+	if a.Migrants < 0 || a.Migrants >= a.PopulationSize {
+		return fmt.Errorf("migrants must be between 0 and one less than the population size, got %d", a.Migrants)
+	}
+	if a.StallGenerations < 0 || a.StallGenerations > MaxGenerations {
+		return fmt.Errorf("stall generations must be between 0 and %d, got %d", MaxGenerations, a.StallGenerations)
+	}
+	return nil
+}
+
+// Function to get the genetic algorithm settings for the request, falling back to the defaults
+func (r *ReqBody) GetAlgorithm() Algorithm {
+	algorithm := DefaultAlgorithm()
+	if r.Algorithm == nil {
+		return algorithm
+	}
+	if r.Algorithm.PopulationSize != nil {
+		algorithm.PopulationSize = *r.Algorithm.PopulationSize
+	}
+	if r.Algorithm.Islands != nil {
+		algorithm.Islands = *r.Algorithm.Islands
+	}
+	if r.Algorithm.Generations != nil {
+		algorithm.Generations = *r.Algorithm.Generations
+	}
+	if r.Algorithm.MutationRate != nil {
+		algorithm.MutationRate = *r.Algorithm.MutationRate
+	}
+	if r.Algorithm.SelectionPressure != nil {
+		algorithm.SelectionPressure = *r.Algorithm.SelectionPressure
+	}
+	if r.Algorithm.SelectionOffset != nil {
+		algorithm.SelectionOffset = *r.Algorithm.SelectionOffset
+	}
+	if r.Algorithm.TournamentGroups != nil {
+		algorithm.TournamentGroups = *r.Algorithm.TournamentGroups
+	}
+	if r.Algorithm.TournamentSize != nil {
+		algorithm.TournamentSize = *r.Algorithm.TournamentSize
+	}
+	if r.Algorithm.Elitism != nil {
+		algorithm.Elitism = *r.Algorithm.Elitism
+	}
+	if r.Algorithm.Topology != "" {
+		algorithm.Topology = r.Algorithm.Topology
+	}
+	if r.Algorithm.MigrationInterval != nil {
+		algorithm.MigrationInterval = *r.Algorithm.MigrationInterval
+	}
+	if r.Algorithm.Migrants != nil {
+		algorithm.Migrants = *r.Algorithm.Migrants
+	}
+	if r.Algorithm.StallGenerations != nil {
+		algorithm.StallGenerations = *r.Algorithm.StallGenerations
+	}
+	return algorithm
+}
+
+// Struct for how a population looked after a generation. Diversity is the number of distinct plans divided by the
+// population size, 1 when no two chromosomes make the same moves, and infeasible chromosomes break the league's
+// acquisition rules
+type GenerationStats struct {
+	Generation int
+	Best       int
+	Mean       float64
+	Worst      int
+	Diversity  float64
+	Infeasible int
+}
+
+// Struct for the details of a run that help tune the algorithm, only sent when the request asks for them
+type Debug struct {
+	Islands [][]GenerationStats
+}
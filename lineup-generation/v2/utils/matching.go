@@ -0,0 +1,124 @@
+// Copy of v1/functions/matching.go and v3/helpers/matching.go, only the package name differs. Each version is its own
+// module, so the copies are kept in sync by TestSharedFilesInSync in v3/tests/shared_files_test.go
+
+package utils
+
+import (
+	"math"
+)
+
+// Function to find the assignment of rows to columns with the highest total weight using the Hungarian algorithm.
+// Pairs weighted -Inf can't be assigned. Returns the column for each row, or -1 if the row is left out
+func MaxWeightAssignment(weights [][]float64) []int {
+
+	rows := len(weights)
+	assignment := make([]int, rows)
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	if rows == 0 {
+		return assignment
+	}
+	cols := len(weights[0])
+
+	// Every row gets its own dummy column with weight 0 so leaving it out is always possible
+	n := rows + cols
+
+	// Turn the weights into non-negative costs, forbidden pairs cost more than any full assignment without them
+	top := 0.0
+	for _, row := range weights {
+		for _, w := range row {
+			if !math.IsInf(w, -1) {
+				top = max(top, w)
+			}
+		}
+	}
+	max_cost := top
+	for _, row := range weights {
+		for _, w := range row {
+			if !math.IsInf(w, -1) {
+				max_cost = max(max_cost, top-w)
+			}
+		}
+	}
+	forbidden := max_cost*float64(n) + 1
+
+	cost := make([][]float64, n)
+	for i := range cost {
+		cost[i] = make([]float64, n)
+		if i >= rows {
+			continue // Padding rows take whatever columns are left for free
+		}
+		for j := range cost[i] {
+			switch {
+			case j >= cols:
+				cost[i][j] = top
+			case math.IsInf(weights[i][j], -1):
+				cost[i][j] = forbidden
+			default:
+				cost[i][j] = top - weights[i][j]
+			}
+		}
+	}
+
+	// Potentials and matching are 1-indexed, column 0 is the row being added
+	u := make([]float64, n+1)
+	v := make([]float64, n+1)
+	match := make([]int, n+1)
+	way := make([]int, n+1)
+	min_v := make([]float64, n+1)
+	used := make([]bool, n+1)
+	for i := 1; i <= n; i++ {
+		match[0] = i
+		j0 := 0
+		for j := range min_v {
+			min_v[j] = math.Inf(1)
+			used[j] = false
+		}
+
+		// Grow an alternating tree until a free column is reached
+		for match[j0] != 0 {
+			used[j0] = true
+			i0 := match[j0]
+			delta := math.Inf(1)
+			j1 := 0
+			for j := 1; j <= n; j++ {
+				if used[j] {
+					continue
+				}
+				if cur := cost[i0-1][j-1] - u[i0] - v[j]; cur < min_v[j] {
+					min_v[j] = cur
+					way[j] = j0
+				}
+				if min_v[j] < delta {
+					delta = min_v[j]
+					j1 = j
+				}
+			}
+			for j := 0; j <= n; j++ {
+				if used[j] {
+					u[match[j]] += delta
+					v[j] -= delta
+				} else {
+					min_v[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+
+		// Flip the augmenting path
+		for j0 != 0 {
+			j1 := way[j0]
+			match[j0] = match[j1]
+			j0 = j1
+		}
+	}
+
+	for j := 1; j <= cols; j++ {
+		if i := match[j] - 1; i >= 0 && i < rows && !math.IsInf(weights[i][j-1], -1) {
+			assignment[i] = j - 1
+		}
+	}
+
+	return assignment
+}
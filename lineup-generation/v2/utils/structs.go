@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"fmt"
 	"sort"
+	"time"
 	d "v2/data"
 )
 
@@ -68,8 +70,153 @@ type ReqBody struct {
 	FreeAgentData []d.Player `json:"free_agent_data"`
 	Threshold     float64    `json:"threshold"`
 	Week          int        `json:"week"`
+	Solver        string     `json:"solver"`
+
+	// Season whose schedule is used, the server's default season when none is given
+	Season string `json:"season"`
+
+	// Date to plan from instead of the week, like 2025-10-21
+	StartDate string `json:"start_date"`
+
+	// Day of the week to re-plan from and the acquisitions already made this week, the plan starts on day 0 when no day is given
+	CurrentDay       *int `json:"current_day"`
+	AcquisitionsUsed int  `json:"acquisitions_used"`
+
+	// League roster layout, the standard layout is used when none is given
+	RosterTemplate *d.RosterTemplate `json:"roster_template"`
+
+	// Objective for slotting the non-streamable players, restrictiveness is used when none is given
+	SlottingMode d.SlottingMode `json:"slotting_mode"`
+
+	// When the league locks lineups, daily locks are used when none is given
+	LockMode d.LockMode `json:"lock_mode"`
+
+	// League transaction rules, only the ones that are given are enforced
+	MaxAcquisitionsPerWeek *int `json:"max_acquisitions_per_week"`
+	MaxAcquisitionsPerDay  *int `json:"max_acquisitions_per_day"`
+	SeasonAcquisitionsLeft *int `json:"season_acquisitions_left"`
+	WaiverDays             *int `json:"waiver_days"`
+
+	// Seed for the genetic algorithm's random draws, the same seed, island count and request always give the same plan.
+	// A new seed is picked when none is given
+	Seed *int64 `json:"seed"`
+
+	// Genetic algorithm settings, the defaults are used for the ones that aren't given
+	Algorithm *AlgorithmRequest `json:"algorithm"`
+
+	// Time the genetic algorithm keeps improving the plan for, it runs for its configured generations when none is given
+	TimeBudgetMs *int `json:"time_budget_ms"`
+
+	// Whether to send back each island's stats for every generation
+	Debug bool `json:"debug"`
+}
+
+// Function to get the roster template for the request, falling back to the default layout without IR slots since v2
+// doesn't plan IR moves
+func (r *ReqBody) GetRosterTemplate() d.RosterTemplate {
+	if r.RosterTemplate == nil {
+		template := d.DefaultRosterTemplate()
+		template.IR = 0
+		return template
+	}
+	return *r.RosterTemplate
+}
+
+// Function to check that the request's roster template can be planned. v2 never moves players into or out of IR or
+// gives their roster spots to streamers, so a template with IR slots is refused instead of quietly planned without them
+func (r *ReqBody) ValidateRosterTemplate() error {
+	template := r.GetRosterTemplate()
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	if template.IR > 0 {
+		return fmt.Errorf("v2 doesn't plan IR moves, set ir to 0 or use v3 for %d IR slots", template.IR)
+	}
+	return nil
+}
+
+// Function to get the acquisition limits for the request, by default one acquisition for each day of the week
+func (r *ReqBody) GetAcquisitionLimits(game_span int) d.AcquisitionLimits {
+	limits := d.DefaultAcquisitionLimits(game_span)
+	if r.MaxAcquisitionsPerWeek != nil {
+		limits.PerWeek = *r.MaxAcquisitionsPerWeek
+	}
+	if r.MaxAcquisitionsPerDay != nil {
+		limits.PerDay = *r.MaxAcquisitionsPerDay
+	}
+	if r.SeasonAcquisitionsLeft != nil {
+		limits.Season = *r.SeasonAcquisitionsLeft
+	}
+	if r.WaiverDays != nil {
+		limits.WaiverDays = *r.WaiverDays
+	}
+	limits.UsedThisWeek = r.AcquisitionsUsed
+	return limits
+}
+
+// Function to get the day of the week to plan from, falling back to the start of the week
+func (r *ReqBody) GetCurrentDay() int {
+	if r.CurrentDay == nil {
+		return 0
+	}
+	return *r.CurrentDay
 }
 
+// Largest seed that is picked for a request, seeds stay below 2^53 so they survive a round trip through JavaScript numbers
+const MaxSeed = 1<<53 - 1
+
+// Function to get the seed for the request, falling back to a new seed from the clock
+func (r *ReqBody) GetSeed() int64 {
+	if r.Seed == nil {
+		return time.Now().UnixNano() & MaxSeed
+	}
+	return *r.Seed
+}
+
+// Longest time budget a request can ask for
+const MaxTimeBudget = 60 * time.Second
+
+// Function to get the request's time budget, 0 when it has none
+func (r *ReqBody) GetTimeBudget() time.Duration {
+	if r.TimeBudgetMs == nil {
+		return 0
+	}
+	return time.Duration(*r.TimeBudgetMs) * time.Millisecond
+}
+
+// Function to check that the time budget, if there is one, is positive and no longer than the longest allowed
+func (r *ReqBody) ValidateTimeBudget() error {
+	if r.TimeBudgetMs == nil {
+		return nil
+	}
+	if budget := r.GetTimeBudget(); budget <= 0 || budget > MaxTimeBudget {
+		return fmt.Errorf("time budget must be between 1 and %d ms, got %d", MaxTimeBudget.Milliseconds(), *r.TimeBudgetMs)
+	}
+	return nil
+}
+
+// Function to get the slotting mode for the request, falling back to restrictiveness
+func (r *ReqBody) GetSlottingMode() d.SlottingMode {
+	if r.SlottingMode == "" {
+		return d.SlotByRestrictiveness
+	}
+	return r.SlottingMode
+}
+
+// Function to get the lock mode for the request, falling back to daily locks
+func (r *ReqBody) GetLockMode() d.LockMode {
+	if r.LockMode == "" {
+		return d.LockDaily
+	}
+	return r.LockMode
+}
+
+// Solvers that can be requested, the genetic algorithm is used when none is given
+const (
+	SolverGenetic = "genetic"
+	SolverExact   = "exact"
+)
+
 // Slimmed version of a player for the response
 type SlimPlayer struct {
 	Name           string
@@ -80,6 +227,7 @@ type SlimPlayer struct {
 // Slimmed version of the final genes for the response
 type SlimGene struct {
 	Day 	  	int
+	Date      string
 	Additions []SlimPlayer
 	Removals  []SlimPlayer
 	Roster	  map[string]SlimPlayer
@@ -92,4 +240,27 @@ type Response struct {
 	Timestamp   string
 	Week        int
 	Threshold   float64
-}
\ No newline at end of file
+	Solver      string
+	Season      string
+
+	// Relative gap between the exact solver's plan and its upper bound, 0 when the plan is proven optimal
+	OptimalityGap *float64 `json:",omitempty"`
+
+	// Seed and number of islands the genetic algorithm ran with, sending both back in the request reproduces the plan on
+	// any server
+	Seed    *int64 `json:",omitempty"`
+	Islands *int   `json:",omitempty"`
+
+	// Whether the solver finished on its own rather than being cut off by its time budget, node limit or the client
+	// disconnecting. A plan that was cut off is the best one found until then
+	Converged bool
+
+	// Stats from the genetic algorithm, only when the request asks for them
+	Debug *Debug `json:",omitempty"`
+}
+// Function to fill in the calendar date of every day in the lineup
+func (r *Response) AddDates(schedule *d.SeasonSchedule) {
+	for i := range r.Lineup {
+		r.Lineup[i].Date = schedule.GetISODate(r.Week, r.Lineup[i].Day)
+	}
+}
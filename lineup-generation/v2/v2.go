@@ -1,13 +1,15 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"net/http"
-	"sync"
 	"time"
 
 	d "v2/data"
+	e "v2/exact"
 	p "v2/population"
 	t "v2/team"
 	u "v2/utils"
@@ -15,6 +17,21 @@ import (
 
 func main() {
 
+	// Schedules for every season are loaded once and reloaded when their files change
+	schedule_dir := flag.String("schedules", "../static", "directory with a schedule file for each season, shared by every version")
+	reload_interval := flag.Duration("reload", d.DefaultReloadInterval, "how often to check the schedule files for changes")
+	default_season := flag.String("season", "", "season used when a request doesn't name one, the latest season by default")
+	flag.Parse()
+
+	registry, err := d.NewScheduleRegistry(*schedule_dir)
+	if err != nil {
+		panic(err)
+	}
+	if *default_season != "" && !registry.HasSeason(*default_season) {
+		panic(fmt.Sprintf("no schedule for season %q in %s", *default_season, *schedule_dir))
+	}
+	go registry.Watch(*reload_interval, nil)
+
 	fmt.Println("Server started on port 8080")
 
 	// Handle request
@@ -38,13 +55,118 @@ func main() {
 			return
 		}
 
+		// Make sure the roster template can be used before optimizing
+		if err := request.ValidateRosterTemplate(); err != nil {
+			http.Error(w, "Invalid roster template: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := request.GetSlottingMode().Validate(); err != nil {
+			http.Error(w, "Invalid slotting mode: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := request.GetLockMode().Validate(); err != nil {
+			http.Error(w, "Invalid lock mode: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The exact solver plans day by day, so it can't keep one lineup for the whole week
+		if request.Solver == u.SolverExact && request.GetLockMode().IsWeekly() {
+			http.Error(w, "The exact solver does not support weekly locks", http.StatusBadRequest)
+			return
+		}
+		if err := request.GetAcquisitionLimits(0).Validate(); err != nil {
+			http.Error(w, "Invalid acquisition limits: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := request.GetAlgorithm().Validate(); err != nil {
+			http.Error(w, "Invalid algorithm settings: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := request.ValidateTimeBudget(); err != nil {
+			http.Error(w, "Invalid time budget: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := d.ValidateInjuries(append(append([]d.Player{}, request.RosterData...), request.FreeAgentData...)); err != nil {
+			http.Error(w, "Invalid injury status: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		// Print the decoded request for debugging purposes
 		fmt.Printf("Received request: Week %d, Threshold %f\n", request.Week, request.Threshold)
 
+		// The request picks the season's schedule, the default season is used when it doesn't name one
+		if request.Season == "" {
+			request.Season = *default_season
+		}
+		if request.Season == "" {
+			request.Season = registry.DefaultSeason()
+		}
+		if !registry.HasSeason(request.Season) {
+			http.Error(w, "Unknown season: "+request.Season, http.StatusBadRequest)
+			return
+		}
+
+		// A start date picks the week that contains it, so a plan can be made from today without knowing the week
+		if request.StartDate != "" {
+			date, err := d.ParseISODate(request.StartDate)
+			if err != nil {
+				http.Error(w, "Invalid start date: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			season_schedule, err := registry.Get(request.Season, d.LoadOptions{})
+			if err != nil {
+				http.Error(w, "Failed to load schedule: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			week, day, err := season_schedule.FindWeek(date)
+			if err != nil {
+				http.Error(w, "Invalid start date: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			request.Week = week
+
+			// Planning from a date in the middle of the week starts on that day
+			if request.CurrentDay == nil {
+				request.CurrentDay = &day
+			}
+		}
+
+		// A schedule that can't be trusted for the requested week is an error rather than an empty lineup
+		season_schedule, err := registry.Get(request.Season, d.LoadOptions{RejectInvalid: true, Weeks: []int{request.Week}})
+		if err != nil {
+			fmt.Println("Error loading schedule:", err)
+			http.Error(w, "Failed to load schedule: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Re-planning starts on a day of the requested week
+		if current_day := request.GetCurrentDay(); current_day < 0 || current_day >= season_schedule.GetGameSpan(request.Week) {
+			http.Error(w, "Invalid current day: outside the week", http.StatusBadRequest)
+			return
+		}
+
 		// Check cache to see if the request has already been made
 
+		// Run the requested solver
+		var response u.Response
+		switch request.Solver {
+		case "", u.SolverGenetic:
+			response = OptimizeStreaming(r.Context(), request, &season_schedule)
+		case u.SolverExact:
+			response = OptimizeExact(request, &season_schedule)
+		default:
+			http.Error(w, "Unknown solver: "+request.Solver, http.StatusBadRequest)
+			return
+		}
+
+		response.Season = request.Season
+		response.AddDates(&season_schedule)
+
+		// The days before the current day are already over, so they aren't part of the plan
+		response.Lineup = response.Lineup[request.GetCurrentDay():]
+
 		// Respond with a JSON-encoded message
-		json_data, err := json.Marshal(OptimizeStreaming(request))
+		json_data, err := json.Marshal(response)
 		if err != nil {
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 			return
@@ -64,64 +186,53 @@ func main() {
 
 }
 
-func OptimizeStreaming(req u.ReqBody) u.Response {
+// Function to find a streaming plan with the genetic algorithm against the request's season schedule
+func OptimizeStreaming(ctx context.Context, req u.ReqBody, schedule *d.SeasonSchedule) u.Response {
 	start := time.Now()
-	d.InitSchedule("./static/schedule25-26.json")
 
 	// Extract request data
 	week := req.Week
 	threshold := req.Threshold
 
 	// Initialize the BaseTeam object with player data from the request
-	bt := t.InitBaseTeam(req.RosterData, req.FreeAgentData, week, threshold)
-
-	// Create new populations
-	ev1 := p.InitPopulation(bt, 20)
-	ev2 := p.InitPopulation(bt, 20)
-
-	// Evolve the populations concurrently
-	var wg sync.WaitGroup
-	wg.Add(2)
-	go func() {
-		defer wg.Done()
-		for range 10 {
-			ev1.Evolve(bt)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		for range 10 {
-			ev2.Evolve(bt)
-		}
-	}()
-	wg.Wait()
-	
-	// Combine the populations
-	ev1.Population = append(ev1.Population, ev2.Population...)
-	ev1.NumChromosomes = len(ev1.Population)
-	fmt.Println("Combined population size: ", ev1.NumChromosomes)
-	
-	// Evolve the combined population
-	for range 10 {
-		ev1.Evolve(bt)
-	}
+	bt := t.InitBaseTeam(schedule, req.RosterData, req.FreeAgentData, week, threshold, req.GetRosterTemplate(), req.GetSlottingMode(), req.GetLockMode())
+	bt.Limits = req.GetAcquisitionLimits(schedule.GetGameSpan(week))
+	bt.CurrentDay = req.GetCurrentDay()
 
-	ev1.SortByFitness()
-	best_chromosome_index := ev1.NumChromosomes - 1
-	for ev1.Population[best_chromosome_index].TotalAcquisitions > d.ScheduleMap.GetGameSpan(week) {
-		best_chromosome_index--
-	}
-	best_chromosome := ev1.Population[best_chromosome_index]
-	best_chromosome.AddBackNonStreamablePlayers(bt)
+	// Create a population for each island, seeded from the request's seed so the run can be reproduced
+	algorithm := req.GetAlgorithm()
+	seed := req.GetSeed()
+	islands := p.InitIslandModel(bt, algorithm, seed)
 
+	// Evolve the islands concurrently, trading the best chromosomes between them as they go. A time budget counted from
+	// the start of the request keeps them evolving until it runs out, and a client that disconnects stops them early
+	var err error
+	if budget := req.GetTimeBudget(); budget > 0 {
+		budget_ctx, cancel := context.WithTimeout(ctx, budget-time.Since(start))
+		defer cancel()
+		err = islands.RunUntilDone(budget_ctx, bt)
+	} else {
+		err = islands.Run(ctx, bt)
+	}
+	if err != nil {
+		fmt.Println("Stopped evolving early:", err)
+	}
 
 	// Get the initial fitness score
 	base_chromosome := p.InitChromosome(bt)
 	for _, gene := range base_chromosome.Genes {
 		gene.InsertStreamablePlayers(bt)
 	}
+	base_chromosome.LockLineups(bt)
 	base_chromosome.ScoreFitness()
 
+	// Take the fittest chromosome found that follows the league's acquisition rules, making no moves always does
+	best_chromosome := base_chromosome
+	if best := islands.Best(); best != nil {
+		best_chromosome = best
+	}
+	best_chromosome.AddBackNonStreamablePlayers(bt)
+
 	// Print the best chromosome
 	fmt.Println(bt.Score + best_chromosome.FitnessScore, "vs", bt.Score + base_chromosome.FitnessScore, "diff", best_chromosome.FitnessScore - base_chromosome.FitnessScore)
 	best_chromosome.Print()
@@ -131,6 +242,37 @@ func OptimizeStreaming(req u.ReqBody) u.Response {
 	current_time := time.Now()
 	layout := "1/2/2006 3:04PM"
 
-	return u.Response{Lineup: best_chromosome.Slim(), Improvement: best_chromosome.FitnessScore - base_chromosome.FitnessScore, Timestamp: current_time.Format(layout), Week: week, Threshold: threshold}
+	response := u.Response{Lineup: best_chromosome.Slim(), Improvement: best_chromosome.FitnessScore - base_chromosome.FitnessScore, Timestamp: current_time.Format(layout), Week: week, Threshold: threshold, Solver: u.SolverGenetic, Seed: &seed, Islands: &algorithm.Islands, Converged: err == nil}
+	if req.Debug {
+		response.Debug = &u.Debug{Islands: islands.Stats()}
+	}
+	return response
+
+}
+
+// Function to find the optimal streaming plan with the exact branch and bound solver
+func OptimizeExact(req u.ReqBody, schedule *d.SeasonSchedule) u.Response {
+	start := time.Now()
+
+	// Extract request data
+	week := req.Week
+	threshold := req.Threshold
+
+	// Initialize the BaseTeam object with player data from the request
+	bt := t.InitBaseTeam(schedule, req.RosterData, req.FreeAgentData, week, threshold, req.GetRosterTemplate(), req.GetSlottingMode(), d.LockDaily)
+	bt.Limits = req.GetAcquisitionLimits(schedule.GetGameSpan(week))
+	bt.CurrentDay = req.GetCurrentDay()
+
+	// Solve with the same acquisition limits the genetic algorithm uses
+	solver := e.InitSolver(bt, bt.Limits.WeeklyLimit(), e.DefaultNodeLimit)
+	result := solver.Solve()
+
+	fmt.Println(result.Score, "vs", result.BaseScore, "bound", result.UpperBound, "optimal", result.Optimal, "nodes", result.Nodes)
+	elapsed := time.Since(start)
+	fmt.Println("Time to run exact solver: ", elapsed)
+
+	current_time := time.Now()
+	layout := "1/2/2006 3:04PM"
 
+	return u.Response{Lineup: solver.Slim(result), Improvement: int(result.Score) - int(result.BaseScore), Timestamp: current_time.Format(layout), Week: week, Threshold: threshold, Solver: u.SolverExact, OptimalityGap: &result.Gap, Converged: result.Optimal}
 }
\ No newline at end of file
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	d "v2/data"
+	u "v2/utils"
+)
+
+// Function to build a one week schedule where every team plays on the given days
+func concurrencyTestSchedule(start string, span int, days map[string][]int) d.SeasonSchedule {
+	games := make(map[string]map[string]bool)
+	for team, team_days := range days {
+		games[team] = make(map[string]bool)
+		for _, day := range team_days {
+			games[team][strconv.Itoa(day)] = true
+		}
+	}
+	return d.SeasonSchedule{Schedule: map[string]d.WeekSchedule{
+		"1": {StartDate: start, GameSpan: span, TeamSchedules: games},
+	}}
+}
+
+// Requests for different seasons run side by side like they do in the server, run with -race to catch shared state
+func TestOptimizeStreamingConcurrent(t *testing.T) {
+	schedules := []d.SeasonSchedule{
+		concurrencyTestSchedule("10/21/2025", 4, map[string][]int{"AAA": {0, 1, 2, 3}, "BBB": {0, 1}, "CCC": {1}, "DDD": {2, 3}, "EEE": {0, 2}, "FFF": {3}}),
+		concurrencyTestSchedule("10/20/2026", 6, map[string][]int{"AAA": {0, 2, 4}, "BBB": {1, 3, 5}, "CCC": {0, 5}, "DDD": {1, 2}, "EEE": {3, 4}, "FFF": {0, 1, 2}}),
+	}
+
+	guard := []string{"PG", "G", "UT1", "UT2", "UT3"}
+	request := u.ReqBody{
+		RosterData: []d.Player{
+			{Name: "Star Center", AvgPoints: 50.0, Team: "AAA", ValidPositions: []string{"C", "UT1", "UT2", "UT3"}},
+			{Name: "Streamer A", AvgPoints: 10.0, Team: "BBB", ValidPositions: guard},
+			{Name: "Streamer B", AvgPoints: 8.0, Team: "CCC", ValidPositions: guard},
+		},
+		FreeAgentData: []d.Player{
+			{Name: "Free Agent 1", AvgPoints: 12.0, Team: "DDD", ValidPositions: guard},
+			{Name: "Free Agent 2", AvgPoints: 9.0, Team: "EEE", ValidPositions: guard},
+			{Name: "Free Agent 3", AvgPoints: 20.0, Team: "FFF", ValidPositions: guard},
+		},
+		Threshold: 30.0,
+		Week:      1,
+	}
+
+	responses := make([]u.Response, 8)
+	var wg sync.WaitGroup
+	for i := range responses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			schedule := schedules[i%len(schedules)]
+			responses[i] = OptimizeStreaming(context.Background(), request, &schedule)
+			responses[i].AddDates(&schedule)
+		}()
+	}
+	wg.Wait()
+
+	// Every plan follows its own season's schedule
+	for i, response := range responses {
+		schedule := schedules[i%len(schedules)]
+		if len(response.Lineup) != schedule.GetGameSpan(1) {
+			t.Errorf("Request %d: expected %d days, got %d", i, schedule.GetGameSpan(1), len(response.Lineup))
+			continue
+		}
+		if date := schedule.GetISODate(1, 0); response.Lineup[0].Date != date {
+			t.Errorf("Request %d: expected the plan to start on %s, got %s", i, date, response.Lineup[0].Date)
+		}
+		if response.Improvement < 0 {
+			t.Errorf("Request %d: expected no worse than making no moves, got %d", i, response.Improvement)
+		}
+	}
+}
+
+// The same seed and request give the same plan, byte for byte
+func TestOptimizeStreamingSeeded(t *testing.T) {
+	schedule := concurrencyTestSchedule("10/21/2025", 6, map[string][]int{"AAA": {0, 2, 4}, "BBB": {1, 3, 5}, "CCC": {0, 5}, "DDD": {1, 2}, "EEE": {3, 4}, "FFF": {0, 1, 2}})
+	guard := []string{"PG", "G", "UT1", "UT2", "UT3"}
+	forward := []string{"SF", "PF", "F", "UT1", "UT2", "UT3"}
+	seed := int64(42)
+	request := u.ReqBody{
+		RosterData: []d.Player{
+			{Name: "Star Center", AvgPoints: 50.0, Team: "AAA", ValidPositions: []string{"C", "UT1", "UT2", "UT3"}},
+			{Name: "Streamer A", AvgPoints: 10.0, Team: "BBB", ValidPositions: guard},
+			{Name: "Streamer B", AvgPoints: 8.0, Team: "CCC", ValidPositions: guard},
+			{Name: "Streamer C", AvgPoints: 7.0, Team: "DDD", ValidPositions: forward},
+
+			// Tied averages, which have to come out of the roster map in the same order on every run
+			{Name: "Streamer D", AvgPoints: 7.0, Team: "EEE", ValidPositions: forward},
+			{Name: "Streamer E", AvgPoints: 7.0, Team: "FFF", ValidPositions: guard},
+		},
+		FreeAgentData: []d.Player{
+			{Name: "Free Agent 1", AvgPoints: 12.0, Team: "DDD", ValidPositions: guard},
+			{Name: "Free Agent 2", AvgPoints: 9.0, Team: "EEE", ValidPositions: guard},
+			{Name: "Free Agent 3", AvgPoints: 20.0, Team: "FFF", ValidPositions: guard},
+			{Name: "Free Agent 4", AvgPoints: 11.0, Team: "EEE", ValidPositions: forward},
+			{Name: "Free Agent 5", AvgPoints: 14.0, Team: "BBB", ValidPositions: forward},
+			{Name: "Free Agent 6", AvgPoints: 13.0, Team: "FFF", ValidPositions: forward},
+		},
+		Threshold: 30.0,
+		Week:      1,
+		Seed:      &seed,
+	}
+
+	plan := func() []byte {
+		response := OptimizeStreaming(context.Background(), request, &schedule)
+		if response.Seed == nil || *response.Seed != seed {
+			t.Fatalf("Expected seed %d in the response, got %v", seed, response.Seed)
+		}
+		response.Timestamp = ""
+		encoded, err := json.Marshal(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return encoded
+	}
+
+	first := plan()
+	for range 5 {
+		if again := plan(); !bytes.Equal(first, again) {
+			t.Fatalf("Expected the same plan for the same seed, got\n%s\nand\n%s", first, again)
+		}
+	}
+
+	// The island count comes back with the seed, so the plan can be replayed on a server with a different number of CPUs
+	response := OptimizeStreaming(context.Background(), request, &schedule)
+	if response.Islands == nil || *response.Islands != u.DefaultAlgorithm().Islands {
+		t.Fatalf("Expected %d islands in the response, got %v", u.DefaultAlgorithm().Islands, response.Islands)
+	}
+	request.Algorithm = &u.AlgorithmRequest{Islands: response.Islands}
+	if replayed := plan(); !bytes.Equal(first, replayed) {
+		t.Errorf("Expected the replayed plan to match, got\n%s\nand\n%s", first, replayed)
+	}
+
+	// Without a seed one is picked and sent back so the run can be repeated
+	request.Seed = nil
+	if response := OptimizeStreaming(context.Background(), request, &schedule); response.Seed == nil {
+		t.Error("Expected the picked seed in the response")
+	}
+}
+
+// A time budget keeps the algorithm running until it's used up, and a cancelled request still gets a plan
+func TestOptimizeStreamingTimeBudget(t *testing.T) {
+	schedule := concurrencyTestSchedule("10/21/2025", 4, map[string][]int{"AAA": {0, 1, 2, 3}, "BBB": {0, 1}, "CCC": {1}, "DDD": {2, 3}})
+	guard := []string{"PG", "G", "UT1", "UT2", "UT3"}
+	budget := 300
+	request := u.ReqBody{
+		RosterData: []d.Player{
+			{Name: "Star Center", AvgPoints: 50.0, Team: "AAA", ValidPositions: []string{"C", "UT1", "UT2", "UT3"}},
+			{Name: "Streamer A", AvgPoints: 10.0, Team: "BBB", ValidPositions: guard},
+		},
+		FreeAgentData: []d.Player{
+			{Name: "Free Agent 1", AvgPoints: 12.0, Team: "DDD", ValidPositions: guard},
+			{Name: "Free Agent 2", AvgPoints: 9.0, Team: "CCC", ValidPositions: guard},
+		},
+		Threshold:    30.0,
+		Week:         1,
+		TimeBudgetMs: &budget,
+	}
+
+	start := time.Now()
+	response := OptimizeStreaming(context.Background(), request, &schedule)
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("Expected the run to take about its 300ms budget, took %v", elapsed)
+	}
+	if response.Converged || len(response.Lineup) != 4 || response.Improvement < 0 {
+		t.Errorf("Expected a plan for the week that was cut off by the budget, got %+v", response)
+	}
+
+	// Without a budget the run finishes its generations
+	request.TimeBudgetMs = nil
+	if response := OptimizeStreaming(context.Background(), request, &schedule); !response.Converged {
+		t.Error("Expected the run to converge without a budget")
+	}
+
+	// A client that's gone stops the run, the plan is the best one found so far
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	response = OptimizeStreaming(ctx, request, &schedule)
+	if response.Converged || len(response.Lineup) != 4 || response.Improvement < 0 {
+		t.Errorf("Expected a plan that was cut off by the cancelled request, got %+v", response)
+	}
+
+	// Budgets have to be positive and at most a minute
+	for _, ms := range []int{0, -5, 60001} {
+		request.TimeBudgetMs = &ms
+		if request.ValidateTimeBudget() == nil {
+			t.Errorf("Expected an error for a %dms budget", ms)
+		}
+	}
+}
+
+// Asking for debug output sends back each island's stats for every generation
+func TestOptimizeStreamingDebug(t *testing.T) {
+	schedule := concurrencyTestSchedule("10/21/2025", 4, map[string][]int{"AAA": {0, 1, 2, 3}, "BBB": {0, 1}, "CCC": {1}, "DDD": {2, 3}})
+	guard := []string{"PG", "G", "UT1", "UT2", "UT3"}
+	islands, generations := 2, 3
+	request := u.ReqBody{
+		RosterData: []d.Player{
+			{Name: "Star Center", AvgPoints: 50.0, Team: "AAA", ValidPositions: []string{"C", "UT1", "UT2", "UT3"}},
+			{Name: "Streamer A", AvgPoints: 10.0, Team: "BBB", ValidPositions: guard},
+		},
+		FreeAgentData: []d.Player{
+			{Name: "Free Agent 1", AvgPoints: 12.0, Team: "DDD", ValidPositions: guard},
+			{Name: "Free Agent 2", AvgPoints: 9.0, Team: "CCC", ValidPositions: guard},
+		},
+		Threshold: 30.0,
+		Week:      1,
+		Algorithm: &u.AlgorithmRequest{Islands: &islands, Generations: &generations},
+	}
+
+	if response := OptimizeStreaming(context.Background(), request, &schedule); response.Debug != nil {
+		t.Error("Expected no debug output unless it's asked for")
+	}
+
+	request.Debug = true
+	response := OptimizeStreaming(context.Background(), request, &schedule)
+	if response.Debug == nil || len(response.Debug.Islands) != islands {
+		t.Fatalf("Expected stats for %d islands, got %+v", islands, response.Debug)
+	}
+	for i, stats := range response.Debug.Islands {
+		if len(stats) != generations+1 {
+			t.Errorf("Island %d: expected stats for the first population and %d generations, got %d", i, generations, len(stats))
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	h "v3/helpers"
+	"v3/schedule"
+)
+
+// Where the NBA publishes the league schedule for the current season
+const LeagueScheduleURL = "https://cdn.nba.com/static/json/staticData/scheduleLeagueV2.json"
+
+const usage = `usage:
+  schedule download -out <raw.json> [-url <url>]
+  schedule build -raw <raw.json> -config <league.json> -out <schedule.json>
+  schedule upgrade -in <legacy.json> -out <schedule.json> [-season <season>]
+  schedule validate [-json] <schedule.json>...`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "download":
+		err = download(os.Args[2:])
+	case "build":
+		err = build(os.Args[2:])
+	case "upgrade":
+		err = upgrade(os.Args[2:])
+	case "validate":
+		err = validate(os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Validation problems are already listed, only the exit code is left to set
+	if errors.Is(err, errInvalidSchedule) {
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// Function to save the raw league schedule to disk so builds never need the network
+func download(args []string) error {
+	flags := flag.NewFlagSet("download", flag.ExitOnError)
+	url := flags.String("url", LeagueScheduleURL, "league schedule to download")
+	out := flags.String("out", "", "file to write the raw schedule to")
+	flags.Parse(args)
+	if *out == "" {
+		return fmt.Errorf("-out is required")
+	}
+
+	response, err := http.Get(*url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed with status %s", response.Status)
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, response.Body); err != nil {
+		return err
+	}
+
+	fmt.Println("Downloaded schedule to", *out)
+	return nil
+}
+
+// Function to build the week schedules from a raw league schedule and a league config
+func build(args []string) error {
+	flags := flag.NewFlagSet("build", flag.ExitOnError)
+	raw_path := flags.String("raw", "", "raw league schedule")
+	config_path := flags.String("config", "", "league config with the week boundaries")
+	out := flags.String("out", "", "file to write the week schedules to")
+	flags.Parse(args)
+	if *raw_path == "" || *config_path == "" || *out == "" {
+		return fmt.Errorf("-raw, -config and -out are required")
+	}
+
+	raw, err := schedule.LoadRawSchedule(*raw_path)
+	if err != nil {
+		return err
+	}
+	config, err := schedule.LoadLeagueConfig(*config_path)
+	if err != nil {
+		return err
+	}
+
+	weeks, err := schedule.Build(raw, config)
+	if err != nil {
+		return err
+	}
+	if err := schedule.WriteSchedule(*out, raw.LeagueSchedule.SeasonYear, weeks); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d weeks to %s\n", len(weeks), *out)
+	return nil
+}
+
+// Function to rewrite a legacy v2 or v3 schedule file in the versioned format
+func upgrade(args []string) error {
+	flags := flag.NewFlagSet("upgrade", flag.ExitOnError)
+	in := flags.String("in", "", "legacy schedule file")
+	out := flags.String("out", "", "file to write the versioned schedule to")
+	season := flags.String("season", "", "season the schedule is for, like 2025-26")
+	flags.Parse(args)
+	if *in == "" || *out == "" {
+		return fmt.Errorf("-in and -out are required")
+	}
+
+	weeks, err := schedule.UpgradeSchedule(*in)
+	if err != nil {
+		return err
+	}
+	if err := schedule.WriteSchedule(*out, *season, weeks); err != nil {
+		return err
+	}
+
+	fmt.Printf("Upgraded %d weeks to %s\n", len(weeks), *out)
+	return nil
+}
+
+// Returned by validate once the problems in a schedule have been printed
+var errInvalidSchedule = errors.New("invalid schedule")
+
+// One problem in the JSON output of validate, with the file it was found in
+type fileProblem struct {
+	File string `json:"file"`
+	h.ScheduleError
+}
+
+// Function to check schedule files and list every problem with the file and line it's on. Warnings are listed but
+// only errors make the check fail
+func validate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	as_json := flags.Bool("json", false, "print the problems of every file as one JSON array")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		return fmt.Errorf("at least one schedule file is required")
+	}
+
+	problems := make([]fileProblem, 0)
+	invalid := false
+	for _, path := range flags.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		errs := h.ValidateSchedule(data)
+		invalid = invalid || errs.Err() != nil
+
+		for _, schedule_err := range errs {
+			problems = append(problems, fileProblem{File: path, ScheduleError: schedule_err})
+		}
+		if *as_json {
+			continue
+		}
+
+		warnings := 0
+		for _, schedule_err := range errs {
+			severity := "error"
+			if schedule_err.Warning {
+				severity = "warning"
+				warnings++
+			}
+			fmt.Printf("%s:%d: %s: %s: %s\n", path, schedule_err.Line, severity, schedule_err.Kind, schedule_err.Message)
+		}
+		if len(errs) == 0 {
+			fmt.Println("No problems found in", path)
+		} else {
+			fmt.Printf("%d errors and %d warnings found in %s\n", len(errs)-warnings, warnings, path)
+		}
+	}
+
+	if *as_json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "    ")
+		if err := encoder.Encode(problems); err != nil {
+			return err
+		}
+	}
+
+	if invalid {
+		return errInvalidSchedule
+	}
+	return nil
+}
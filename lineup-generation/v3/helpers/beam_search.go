@@ -0,0 +1,435 @@
+package helpers
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Number of states kept at each step of the beam search
+const BeamWidth = 10
+
+// Number of states the widest beam keeps when a time budget lets the search keep widening
+const MaxBeamWidth = 160
+
+// Beam search over add/drop moves, starting from the root state and returning the best state at the end of the week
+func BeamSearch(schedule *WeekSchedule, ssm *SetupStateMetadata, root *State, beam_width int) *State {
+	best, _ := BeamSearchContext(context.Background(), schedule, ssm, root, beam_width)
+	return best
+}
+
+// Beam search that stops when the context is done. The states it has reached are then carried to the end of the plan
+// without more moves, and the best of them is returned along with the context's error
+func BeamSearchContext(ctx context.Context, schedule *WeekSchedule, ssm *SetupStateMetadata, root *State, beam_width int) (*State, error) {
+
+	beam := []*State{root.Copy()}
+
+	for day := root.day; day < schedule.GetGameSpan(); day++ {
+
+		// Players coming back from IR take their roster spots back before any moves are made
+		if day > root.day {
+			for _, state := range beam {
+				state.ActivateReturningPlayers(schedule, ssm)
+			}
+		}
+
+		// Keep expanding the beam on the same day so that multiple moves can be made on one day
+		candidates := append([]*State{}, beam...)
+		frontier := beam
+		for len(frontier) > 0 && ctx.Err() == nil {
+			successors := make([]*State, 0)
+			for _, state := range frontier {
+				if ctx.Err() != nil {
+					break
+				}
+				successors = append(successors, state.GetSuccessors(schedule, ssm)...)
+			}
+			frontier = TopStates(successors, beam_width)
+			candidates = append(candidates, frontier...)
+		}
+		beam = TopStates(candidates, beam_width)
+		if ctx.Err() != nil {
+			break
+		}
+
+		// Move the surviving states on to the next day
+		if day < schedule.GetGameSpan()-1 {
+			for _, state := range beam {
+				state.AdvanceDay()
+			}
+		}
+	}
+
+	// States that were cut off keep their streamers for the rest of the plan
+	err := ctx.Err()
+	if err != nil {
+		for _, state := range beam {
+			state.FinishWithoutMoves(schedule, ssm)
+		}
+		beam = TopStates(beam, beam_width)
+	}
+
+	// Take the best state that follows the league's acquisition rules, the root state makes no moves so it always does
+	for _, state := range beam {
+		if state.IsWithinLimits() {
+			return state, err
+		}
+	}
+	best := root.Copy()
+	best.FinishWithoutMoves(schedule, ssm)
+	return best, err
+}
+
+// Function to search with a wider beam each time until the widest beam is done or the context is, keeping the best
+// state any of the searches found. It returns the context's error when the widest search didn't finish
+func WideningBeamSearch(ctx context.Context, schedule *WeekSchedule, ssm *SetupStateMetadata, root *State) (*State, error) {
+	var best *State
+	for beam_width := BeamWidth; beam_width <= MaxBeamWidth; beam_width *= 2 {
+		state, err := BeamSearchContext(ctx, schedule, ssm, root, beam_width)
+		if best == nil || state.score > best.score {
+			best = state
+		}
+		if err != nil {
+			return best, err
+		}
+	}
+	return best, nil
+}
+
+// Function to carry a state to the end of the plan without making more moves, players coming back from IR are still
+// activated on their return days
+func (s *State) FinishWithoutMoves(schedule *WeekSchedule, ssm *SetupStateMetadata) {
+	for s.day < schedule.GetGameSpan()-1 {
+		s.AdvanceDay()
+		s.ActivateReturningPlayers(schedule, ssm)
+	}
+}
+
+// Function to check if the acquisitions made on each day follow the league's limits, each week has its own budget
+func (s *State) IsWithinLimits() bool {
+	used := 0
+	for i, start := range s.week_starts {
+		end := len(s.lineups)
+		if i+1 < len(s.week_starts) {
+			end = s.week_starts[i+1]
+		}
+
+		daily_counts := make([]int, 0, end-start)
+		for _, lineup := range s.lineups[start:end] {
+			daily_counts = append(daily_counts, len(lineup.additions))
+		}
+		// The first week keeps the acquisitions made before the plan started, later weeks start fresh
+		week_limits := s.limits
+		if i > 0 {
+			week_limits = s.limits.ForWeek(used)
+		}
+		if !week_limits.Allows(daily_counts) {
+			return false
+		}
+		for _, count := range daily_counts {
+			used += count
+		}
+	}
+	return true
+}
+
+// Function to check if a new week starts on [day]
+func (s *State) IsWeekStart(day int) bool {
+	return slices.Contains(s.week_starts, day)
+}
+
+// Function to get the limits for a later week of a plan, the season budget shrinks by the [used] acquisitions already
+// made and the week starts with none used
+func (al AcquisitionLimits) ForWeek(used int) AcquisitionLimits {
+	if al.Season != NoLimit {
+		al.Season = max(al.Season-used, 0)
+	}
+	al.UsedThisWeek = 0
+	return al
+}
+
+// Function to get the limits for the current week, the season budget shrinks by the acquisitions made in earlier weeks
+func (s *State) GetWeekLimits() AcquisitionLimits {
+	week_start := 0
+	for _, start := range s.week_starts {
+		if start <= s.day {
+			week_start = start
+		}
+	}
+	if week_start == 0 {
+		return s.limits
+	}
+
+	used := 0
+	for _, lineup := range s.lineups[:week_start] {
+		used += len(lineup.additions)
+	}
+	return s.limits.ForWeek(used)
+}
+
+// Function to sort states by score and keep the best [k] distinct states
+func TopStates(states []*State, k int) []*State {
+
+	// Stable sort so that ties keep the order they were generated in
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].score > states[j].score
+	})
+
+	top := make([]*State, 0, k)
+	seen := make(map[string]bool)
+	for _, state := range states {
+		if len(top) == k {
+			break
+		}
+
+		// States that end up with the same streamers on the same day are duplicates, keep the best one
+		key := state.Key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		top = append(top, state)
+	}
+
+	return top
+}
+
+// Function to get every state reachable from this one by making a single add/drop move on the current day
+func (s *State) GetSuccessors(schedule *WeekSchedule, ssm *SetupStateMetadata) []*State {
+
+	successors := make([]*State, 0)
+
+	// With a weekly lock every pickup has to be made before the lineup locks on the first day of the week
+	if s.lock_mode.IsWeekly() && !s.IsWeekStart(s.day) {
+		return successors
+	}
+	limits := s.GetWeekLimits()
+	if s.acq_left <= 0 || limits.Available(limits.WeeklyLimit()-s.acq_left, len(s.lineups[s.day].additions)) == 0 {
+		return successors
+	}
+
+	for _, free_agent := range s.free_agents {
+
+		// Only pick up healthy players that are playing today and have cleared waivers. Under a weekly lock a game
+		// later in the week is enough
+		playing := schedule.IsPlaying(s.day, free_agent.Team) && !schedule.IsResting(free_agent, s.day)
+		if s.lock_mode.IsWeekly() {
+			_, end := schedule.GetWeekBounds(s.day)
+			playing = schedule.GetGamesInWeek(free_agent.Team, s.day) > 0 && !free_agent.IsOutBetween(s.day, end)
+		}
+		if !playing || free_agent.IsOnWaivers(s.day) || s.IsDropped(free_agent) {
+			continue
+		}
+
+		for _, streamer := range s.current_streamers {
+
+			// Don't drop a player that was picked up on the same day
+			if ContainsPlayer(s.lineups[s.day].additions, streamer) {
+				continue
+			}
+
+			// Only keep moves that improve the score, otherwise the acquisition is wasted
+			successor := s.MakeMove(schedule, ssm, streamer, free_agent)
+			if successor.score > s.score {
+				successors = append(successors, successor)
+			}
+		}
+	}
+
+	return successors
+}
+
+// Function to create a new state where [to_drop] is dropped and [to_add] is picked up on the current day
+func (s *State) MakeMove(schedule *WeekSchedule, ssm *SetupStateMetadata, to_drop Player, to_add Player) *State {
+
+	next := s.Copy()
+
+	// Replace the dropped streamer and keep the streamers sorted by average points
+	for i, streamer := range next.current_streamers {
+		if streamer.Name == to_drop.Name {
+			next.current_streamers[i] = to_add
+			break
+		}
+	}
+	sort.SliceStable(next.current_streamers, func(i, j int) bool {
+		return next.current_streamers[i].AvgPoints > next.current_streamers[j].AvgPoints
+	})
+
+	// Swap the players between the roster and the free agent pool
+	free_agents := make([]Player, 0, len(next.free_agents))
+	for _, free_agent := range next.free_agents {
+		if free_agent.Name != to_add.Name {
+			free_agents = append(free_agents, free_agent)
+		}
+	}
+	next.free_agents = free_agents
+
+	// Record the transaction on the current day, filling an open roster spot doesn't drop anyone
+	next.lineups[next.day].additions = append(next.lineups[next.day].additions, to_add)
+	if !to_drop.IsOpenRosterSpot() {
+		next.free_agents = append(next.free_agents, to_drop)
+		next.dropped_players = append(next.dropped_players, DroppedPlayer{Player: to_drop, Countdown: next.limits.WaiverDays})
+		next.lineups[next.day].removals = append(next.lineups[next.day].removals, to_drop)
+	}
+	next.acq_left--
+
+	// Re-slot the streamers for the rest of the week
+	next.ResetLineups(ssm, next.day)
+	next.SlotStreamers(schedule, false)
+	next.ScoreLineup()
+
+	return next
+}
+
+// Function to clear the streamers out of the lineups from [start] on, keeping the recorded transactions
+func (s *State) ResetLineups(ssm *SetupStateMetadata, start int) {
+	for day := start; day < len(s.lineups); day++ {
+		additions := s.lineups[day].additions
+		removals := s.lineups[day].removals
+		actions := s.lineups[day].actions
+
+		s.lineups[day] = NewLineup(ssm.unused_positions[day])
+		s.lineups[day].additions = additions
+		s.lineups[day].removals = removals
+		s.lineups[day].actions = actions
+	}
+}
+
+// Function to move the state to the next day and count down the waiver period of dropped players. A new week
+// starts with a fresh acquisition budget
+func (s *State) AdvanceDay() {
+	s.day++
+	if s.IsWeekStart(s.day) {
+		s.acq_left = s.GetWeekLimits().WeeklyLimit()
+	}
+
+	dropped_players := make([]DroppedPlayer, 0, len(s.dropped_players))
+	for _, dropped_player := range s.dropped_players {
+		dropped_player.Countdown--
+		if dropped_player.Countdown > 0 {
+			dropped_players = append(dropped_players, dropped_player)
+		}
+	}
+	s.dropped_players = dropped_players
+}
+
+// Function to check if a player is still on waivers after being dropped
+func (s *State) IsDropped(player Player) bool {
+	for _, dropped_player := range s.dropped_players {
+		if dropped_player.Player.Name == player.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Function to get a key that identifies the state's day, remaining acquisitions and streamers
+func (s *State) Key() string {
+	names := make([]string, len(s.current_streamers))
+	for i, streamer := range s.current_streamers {
+		names[i] = streamer.Name
+	}
+	sort.Strings(names)
+
+	return strconv.Itoa(s.day) + "|" + strconv.Itoa(s.acq_left) + "|" + strings.Join(names, ",")
+}
+
+// Function to deep copy a state so that successors don't share lineups
+func (s *State) Copy() *State {
+	next := &State{
+		day:               s.day,
+		start_day:         s.start_day,
+		score:             s.score,
+		acq_left:          s.acq_left,
+		lineups:           make([]Lineup, len(s.lineups)),
+		free_agents:       make([]Player, len(s.free_agents)),
+		current_streamers: make([]Player, len(s.current_streamers)),
+		dropped_players:   make([]DroppedPlayer, len(s.dropped_players)),
+		template:          s.template,
+		limits:            s.limits,
+		lock_mode:         s.lock_mode,
+		week_starts:       s.week_starts,
+		ir_moves:          append([]IRMove{}, s.ir_moves...),
+	}
+	for i, lineup := range s.lineups {
+		next.lineups[i] = lineup.Copy()
+	}
+	copy(next.free_agents, s.free_agents)
+	copy(next.current_streamers, s.current_streamers)
+	copy(next.dropped_players, s.dropped_players)
+
+	return next
+}
+
+// Function to deep copy a lineup
+func (l *Lineup) Copy() Lineup {
+	lineup := Lineup{
+		roster:    make(map[string]Player, len(l.roster)),
+		bench:     make([]Player, len(l.bench)),
+		additions: make([]Player, len(l.additions)),
+		removals:  make([]Player, len(l.removals)),
+		actions:   append([]RosterAction{}, l.actions...),
+		score:     l.score,
+	}
+	for position, player := range l.roster {
+		lineup.roster[position] = player
+	}
+	copy(lineup.bench, l.bench)
+	copy(lineup.additions, l.additions)
+	copy(lineup.removals, l.removals)
+
+	return lineup
+}
+
+// Function to split rosters that span several weeks of [schedule] into a plan for each of the [weeks]
+func SplitByWeek(schedule *WeekSchedule, weeks []int, rosters []Roster) []WeekPlan {
+
+	plan := make([]WeekPlan, len(schedule.GetWeekStarts()))
+	for i := range plan {
+		plan[i] = WeekPlan{Week: weeks[i], Lineup: make([]Roster, 0)}
+	}
+	for _, roster := range rosters {
+		index := schedule.GetWeekIndex(roster.Day)
+		start, _ := schedule.GetWeekBounds(roster.Day)
+		roster.Week = weeks[index]
+		roster.Date = schedule.GetISODate(roster.Day)
+		roster.Day -= start
+		plan[index].Lineup = append(plan[index].Lineup, roster)
+	}
+
+	return plan
+}
+
+// Function to convert the state's lineups into the response format, adding back the non-streamable players
+func (s *State) ToRosters(ssm *SetupStateMetadata) []Roster {
+
+	// Days before the plan started are already over, so they aren't part of it
+	rosters := make([]Roster, 0, len(s.lineups)-s.start_day)
+	for day, lineup := range s.lineups {
+		if day < s.start_day {
+			continue
+		}
+		roster := Roster{
+			Day:       day,
+			Additions: append([]Player{}, lineup.additions...),
+			Removals:  append([]Player{}, lineup.removals...),
+			Actions:   append([]RosterAction{}, lineup.actions...),
+			Roster:    make(map[string]Player),
+		}
+
+		for position, player := range ssm.optimal_slotting[day] {
+			roster.Roster[position] = player
+		}
+		for position, player := range lineup.roster {
+			if player.Name != "" {
+				roster.Roster[position] = player
+			}
+		}
+
+		rosters = append(rosters, roster)
+	}
+
+	return rosters
+}
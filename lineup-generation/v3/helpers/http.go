@@ -1,11 +1,110 @@
 package helpers
 
+import (
+	"fmt"
+	"time"
+)
+
 // Incoming request body to begin the lineup generation process
 type Request struct {
 	RosterData []Player     `json:"roster_data"`
 	FreeAgentData []Player  `json:"free_agent_data"`
 	Threshold float64   		`json:"threshold"`
 	Week int    				    `json:"week"`
+	Weeks []int `json:"weeks"`
+	Season string `json:"season"`
+	StartDate string `json:"start_date"`
+	CurrentDay *int `json:"current_day"`
+	AcquisitionsUsed int `json:"acquisitions_used"`
+	RosterTemplate *RosterTemplate `json:"roster_template"`
+	SlottingMode SlottingMode `json:"slotting_mode"`
+	LockMode LockMode `json:"lock_mode"`
+	MaxAcquisitionsPerWeek *int `json:"max_acquisitions_per_week"`
+	MaxAcquisitionsPerDay *int `json:"max_acquisitions_per_day"`
+	SeasonAcquisitionsLeft *int `json:"season_acquisitions_left"`
+	WaiverDays *int `json:"waiver_days"`
+	TimeBudgetMs *int `json:"time_budget_ms"`
+}
+
+// Function to get the weeks to plan over, falling back to the single requested week
+func (r *Request) GetWeeks() []int {
+	if len(r.Weeks) == 0 {
+		return []int{r.Week}
+	}
+	return r.Weeks
+}
+
+// Function to get the roster template for the request, falling back to the default layout
+func (r *Request) GetRosterTemplate() RosterTemplate {
+	if r.RosterTemplate == nil {
+		return DefaultRosterTemplate()
+	}
+	return *r.RosterTemplate
+}
+
+// Function to get the acquisition limits for the request, by default one acquisition for each day of the week
+func (r *Request) GetAcquisitionLimits(game_span int) AcquisitionLimits {
+	limits := DefaultAcquisitionLimits(game_span)
+	if r.MaxAcquisitionsPerWeek != nil {
+		limits.PerWeek = *r.MaxAcquisitionsPerWeek
+	}
+	if r.MaxAcquisitionsPerDay != nil {
+		limits.PerDay = *r.MaxAcquisitionsPerDay
+	}
+	if r.SeasonAcquisitionsLeft != nil {
+		limits.Season = *r.SeasonAcquisitionsLeft
+	}
+	if r.WaiverDays != nil {
+		limits.WaiverDays = *r.WaiverDays
+	}
+	limits.UsedThisWeek = r.AcquisitionsUsed
+	return limits
+}
+
+// Function to get the day of the first week to plan from, falling back to the start of the week
+func (r *Request) GetCurrentDay() int {
+	if r.CurrentDay == nil {
+		return 0
+	}
+	return *r.CurrentDay
+}
+
+// Function to get the slotting mode for the request, falling back to restrictiveness
+func (r *Request) GetSlottingMode() SlottingMode {
+	if r.SlottingMode == "" {
+		return SlotByRestrictiveness
+	}
+	return r.SlottingMode
+}
+
+// Function to get the lock mode for the request, falling back to daily locks
+func (r *Request) GetLockMode() LockMode {
+	if r.LockMode == "" {
+		return LockDaily
+	}
+	return r.LockMode
+}
+
+// Longest time budget a request can ask for
+const MaxTimeBudget = 60 * time.Second
+
+// Function to get the request's time budget, 0 when it has none
+func (r *Request) GetTimeBudget() time.Duration {
+	if r.TimeBudgetMs == nil {
+		return 0
+	}
+	return time.Duration(*r.TimeBudgetMs) * time.Millisecond
+}
+
+// Function to check that the time budget, if there is one, is positive and no longer than the longest allowed
+func (r *Request) ValidateTimeBudget() error {
+	if r.TimeBudgetMs == nil {
+		return nil
+	}
+	if budget := r.GetTimeBudget(); budget <= 0 || budget > MaxTimeBudget {
+		return fmt.Errorf("time budget must be between 1 and %d ms, got %d", MaxTimeBudget.Milliseconds(), *r.TimeBudgetMs)
+	}
+	return nil
 }
 
 type Response struct {
@@ -14,11 +113,25 @@ type Response struct {
 	Timestamp string
 	Week int
 	Threshold float64
+	Plan []WeekPlan
+	Season string
+
+	// Whether the search finished on its own rather than being cut off by the time budget or the client disconnecting
+	Converged bool
+}
+
+// Plan for one week of the horizon, the days of its rosters count from the start of that week
+type WeekPlan struct {
+	Week   int
+	Lineup []Roster
 }
 
 type Roster struct {
+	Week      int
 	Day 	  	int
+	Date      string
 	Additions []Player
 	Removals  []Player
+	Actions   []RosterAction
 	Roster	  map[string]Player
 }
\ No newline at end of file
@@ -0,0 +1,157 @@
+package helpers
+
+import "sort"
+
+// Actions on a roster that move a player into or out of an IR slot
+const (
+	ActionMoveToIR = "move_to_ir"
+	ActionActivate = "activate"
+)
+
+// Name of the placeholder streamer that stands for a roster spot freed by moving a player into IR
+const OpenRosterSpotName = "Open Roster Spot"
+
+// Struct for a move into or out of an IR slot, recorded on the day it's made
+type RosterAction struct {
+	Action string
+	Player Player
+}
+
+// Struct for an injured player who spends part of the plan in an IR slot. He's activated on [ReturnDay], which is past
+// the end of the plan when he doesn't come back in time. Players who were already in IR aren't moved there again
+type IRMove struct {
+	Player    Player
+	ReturnDay int
+	MoveToIR  bool
+}
+
+// Function to get the placeholder for a free roster spot, picking up a player into it doesn't need a drop
+func OpenRosterSpot() Player {
+	return Player{Name: OpenRosterSpotName}
+}
+
+// Function that returns whether a player is the placeholder for a free roster spot
+func (p Player) IsOpenRosterSpot() bool {
+	return p.Name == OpenRosterSpotName
+}
+
+// Function that returns whether a player can be moved into an IR slot, only players ruled out can
+func (p Player) IsIREligible() bool {
+	status := p.GetInjuryStatus()
+	return status == InjuryOut || status == InjuryReserve
+}
+
+// Function to plan who spends time in an IR slot. Players already in IR keep their slots, the free ones go to the
+// injured players worth keeping who are out the longest, and everyone is activated on the day he returns
+func (ssm *SetupStateMetadata) PlanInjuredReserve(schedule *WeekSchedule, threshold float64) []IRMove {
+
+	// A player is activated on the first day he has a chance of playing, which is when slotting brings him back
+	return_day := func(player Player) int {
+		for day := 0; day < schedule.GetGameSpan(); day++ {
+			if player.PlayProbability(day) > 0 {
+				return day
+			}
+		}
+		return schedule.GetGameSpan()
+	}
+
+	moves := make([]IRMove, 0)
+	candidates := make([]IRMove, 0)
+	free_slots := ssm.template.IR
+	for _, player := range ssm.roster {
+		switch {
+		case player.OnIR:
+			moves = append(moves, IRMove{Player: player, ReturnDay: return_day(player)})
+			free_slots--
+		case player.IsIREligible() && player.AvgPoints > threshold:
+			candidates = append(candidates, IRMove{Player: player, ReturnDay: return_day(player), MoveToIR: true})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].ReturnDay > candidates[j].ReturnDay
+	})
+	if free_slots > 0 {
+		moves = append(moves, candidates[:min(free_slots, len(candidates))]...)
+	}
+
+	return moves
+}
+
+// Function to find the day each current streamer leaves the roster to make room for players coming back from IR, if no
+// more moves are made. Open roster spots are given up first, then the streamers with the fewest average points
+func (s *State) GetReturnDrops() []int {
+
+	until := make([]int, len(s.current_streamers))
+	for i := range until {
+		until[i] = len(s.lineups)
+	}
+
+	returns := make([]int, 0, len(s.ir_moves))
+	for _, move := range s.ir_moves {
+		if move.ReturnDay < len(s.lineups) {
+			returns = append(returns, move.ReturnDay)
+		}
+	}
+	sort.Ints(returns)
+
+	for _, day := range returns {
+		drop := -1
+		for i, streamer := range s.current_streamers {
+			if until[i] < len(s.lineups) {
+				continue
+			}
+			if streamer.IsOpenRosterSpot() {
+				drop = i
+				break
+			}
+
+			// Streamers are sorted by average points, so the last one left has the fewest
+			drop = i
+		}
+		if drop >= 0 {
+			until[drop] = day
+		}
+	}
+
+	return until
+}
+
+// Function to activate the players returning from IR on the current day, each one takes back a roster spot before any
+// moves are made
+func (s *State) ActivateReturningPlayers(schedule *WeekSchedule, ssm *SetupStateMetadata) {
+
+	until := s.GetReturnDrops()
+
+	pending := make([]IRMove, 0, len(s.ir_moves))
+	for _, move := range s.ir_moves {
+		if move.ReturnDay != s.day {
+			pending = append(pending, move)
+			continue
+		}
+		s.lineups[s.day].actions = append(s.lineups[s.day].actions, RosterAction{Action: ActionActivate, Player: move.Player})
+	}
+	if len(pending) == len(s.ir_moves) {
+		return
+	}
+	s.ir_moves = pending
+
+	// Drop the streamers making room, an open spot is just given back
+	streamers := make([]Player, 0, len(s.current_streamers))
+	for i, streamer := range s.current_streamers {
+		if until[i] != s.day {
+			streamers = append(streamers, streamer)
+			continue
+		}
+		if !streamer.IsOpenRosterSpot() {
+			s.lineups[s.day].removals = append(s.lineups[s.day].removals, streamer)
+			s.free_agents = append(s.free_agents, streamer)
+			s.dropped_players = append(s.dropped_players, DroppedPlayer{Player: streamer, Countdown: s.limits.WaiverDays})
+		}
+	}
+	s.current_streamers = streamers
+
+	s.ResetLineups(ssm, s.day)
+	s.SlotStreamers(schedule, false)
+	s.ScoreLineup()
+}
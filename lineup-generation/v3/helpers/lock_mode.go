@@ -0,0 +1,36 @@
+// Copy of v2/data/lock_mode.go, only the package name differs. Each version is its own module, so the copies are
+// kept in sync by TestSharedFilesInSync in v3/tests/shared_files_test.go
+
+package helpers
+
+import "fmt"
+
+// When a league locks lineups, which decides how often the starting lineup can change
+type LockMode string
+
+const (
+	// Lineups can be changed every day
+	LockDaily LockMode = "daily"
+	// One starting lineup is set before the first game and kept for the whole week
+	LockWeekly LockMode = "weekly"
+	// Each player locks at tip-off. Plans are made a day at a time without game times, so it's refused until a player
+	// whose game has started can be kept from being swapped out
+	LockPerGame LockMode = "per_game"
+)
+
+// Function to check that a lock mode is known and can be planned
+func (m LockMode) Validate() error {
+	switch m {
+	case LockDaily, LockWeekly:
+		return nil
+	case LockPerGame:
+		return fmt.Errorf("lock mode %q isn't supported yet, use %q", string(m), string(LockDaily))
+	default:
+		return fmt.Errorf("unknown lock mode %q", string(m))
+	}
+}
+
+// Function to check if the lineup is fixed for the whole week
+func (m LockMode) IsWeekly() bool {
+	return m == LockWeekly
+}
@@ -1,5 +1,7 @@
 package helpers
 
+import "strconv"
+
 // Struct for how to contruct Players using the returned player data
 type Player struct {
 	Name           string   `json:"name"`
@@ -7,6 +9,17 @@ type Player struct {
 	Team           string   `json:"team"`
 	ValidPositions []string `json:"valid_positions"`
 	Injured        bool     `json:"injured"`
+	WaiverUntilDay int      `json:"waiver_until_day"`
+
+	// Injury report status and the day of the plan the player is expected back, an injured player without a status is out
+	InjuryStatus InjuryStatus `json:"injury_status"`
+	ReturnDay    *int         `json:"return_day"`
+
+	// Whether the player is already in one of the roster's IR slots
+	OnIR bool `json:"on_ir"`
+
+	// Expected points per game keyed by day of the plan or by date like 2025-10-21, AvgPoints is used for games without one
+	Projections map[string]float64 `json:"projections"`
 }
 
 func (p Player) PlaysPosition(position string) bool {
@@ -18,8 +31,43 @@ func (p Player) PlaysPosition(position string) bool {
 	return false
 }
 
+// Function to check if a slice of players contains a player
+func ContainsPlayer(players []Player, player Player) bool {
+	for _, p := range players {
+		if p.Name == player.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Function that returns whether a player is still on waivers on a day of the week. Players clear waivers on
+// [WaiverUntilDay] and can be picked up from then on
+func (p Player) IsOnWaivers(day int) bool {
+	return day < p.WaiverUntilDay
+}
+
+// Function that returns a player's projected points for a game on [day] or [date], a projection for the day comes first
+func (p Player) GetProjection(day int, date string) (float64, bool) {
+	if points, ok := p.Projections[strconv.Itoa(day)]; ok {
+		return points, true
+	}
+	if points, ok := p.Projections[date]; ok && date != "" {
+		return points, true
+	}
+	return 0, false
+}
+
+// Function that returns a player's expected points for a game on [day] or [date], his average when there's no projection
+func (p Player) GetProjectedPoints(day int, date string) float64 {
+	if points, ok := p.GetProjection(day, date); ok {
+		return points
+	}
+	return p.AvgPoints
+}
+
 // Struct for organizing data on a player who has been dropped
 type DroppedPlayer struct {
 	Player 	  Player
-	Countdown int
+	Countdown int // Days left before the player clears waivers
 }
\ No newline at end of file
@@ -1,29 +1,67 @@
 package helpers
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"strconv"
 	"slices"
+	"time"
 )
 
+// Layout of the dates in requests and responses
+const ISODateLayout = "2006-01-02"
+
 // Struct to hold a single week's schedule
 type WeekSchedule struct {
 	StartDate     string           	   	  	 `json:"startDate"`
 	EndDate       string           	      	 `json:"endDate"`
 	GameSpan  	  int                     	 `json:"gameSpan"`
 	TeamSchedules map[string][]int 					 `json:"games"`
+
+	// Opponent, home/away and tip-off for each game, only filled from versioned schedule files
+	Games map[string][]Game `json:"-"`
+
+	// League weeks stitched into this schedule and the day each one starts on, empty for a single week
+	Weeks      []int `json:"-"`
+	WeekStarts []int `json:"-"`
+}
+
+// Options for loading a schedule file
+type LoadOptions struct {
+	// Refuse weeks that don't pass ValidateSchedule instead of planning with them
+	RejectInvalid bool
 }
 
 // InitWeekSchedule loads only the specific week's schedule data
 func LoadWeekSchedule(path string, week int) (WeekSchedule, error) {
+	return LoadWeekScheduleWithOptions(path, week, LoadOptions{})
+}
+
+// Function to load a single week's schedule, refusing the week if the file has problems with it when asked to
+func LoadWeekScheduleWithOptions(path string, week int, options LoadOptions) (WeekSchedule, error) {
+	fullSchedule, errs, err := readSeasonSchedule(path, options)
+	if err != nil {
+		return WeekSchedule{}, err
+	}
+
+	weekData, err := SelectWeek(fullSchedule, errs, week, options)
+	if err != nil {
+		fmt.Printf("Error loading schedule for week %d:\n%v\n", week, err)
+		return WeekSchedule{}, err
+	}
+
+	fmt.Printf("Loaded schedule for week %d\n", week)
+	return weekData, nil
+}
+
+// Function to read and parse a full schedule file, checking it for problems when the options ask for it
+func readSeasonSchedule(path string, options LoadOptions) (map[string]WeekSchedule, ScheduleErrors, error) {
 	// Load JSON schedule file
 	json_schedule, err := os.Open(path)
 	if err != nil {
 		fmt.Println("Error opening json schedule:", err)
-		return WeekSchedule{}, err
+		return nil, nil, err
 	}
 	defer json_schedule.Close()
 
@@ -31,28 +69,196 @@ func LoadWeekSchedule(path string, week int) (WeekSchedule, error) {
 	jsonBytes, err := io.ReadAll(json_schedule)
 	if err != nil {
 		fmt.Println("Error reading json schedule:", err)
-		return WeekSchedule{}, err
+		return nil, nil, err
+	}
+
+	// Problems that aren't tied to a week, like bad JSON, make the whole file unusable
+	var errs ScheduleErrors
+	if options.RejectInvalid {
+		errs = ValidateSchedule(jsonBytes)
+		if err := errs.ForWeeks().Err(); err != nil {
+			fmt.Printf("Invalid schedule:\n%v\n", err)
+			return nil, nil, err
+		}
 	}
 
-	// Parse the full schedule to get the specific week
-	var fullSchedule map[string]WeekSchedule
-	err = json.Unmarshal(jsonBytes, &fullSchedule)
+	// Parse the full schedule, versioned and legacy files are both understood
+	fullSchedule, err := ParseSeasonSchedule(jsonBytes)
 	if err != nil {
 		fmt.Println("Error parsing schedule:", err)
-		return WeekSchedule{}, err
+		return nil, nil, err
+	}
+	return fullSchedule, errs, nil
+}
+
+// Function to pick one week out of a season's schedule, refusing it if [errs] has problems with it when asked to
+func SelectWeek(weeks map[string]WeekSchedule, errs ScheduleErrors, week int, options LoadOptions) (WeekSchedule, error) {
+	// A week with problems would quietly plan with missing or misplaced games
+	if options.RejectInvalid {
+		if err := errs.ForWeeks(week).Err(); err != nil {
+			return WeekSchedule{}, err
+		}
 	}
 
-	// Get the specific week
-	weekKey := strconv.Itoa(week)
-	weekData, exists := fullSchedule[weekKey]
+	weekData, exists := weeks[strconv.Itoa(week)]
 	if !exists {
 		return WeekSchedule{}, fmt.Errorf("week %d not found in schedule", week)
 	}
-
-	fmt.Printf("Loaded schedule for week %d\n", week)
+	weekData.Weeks = []int{week}
+	weekData.WeekStarts = []int{0}
 	return weekData, nil
 }
 
+// Function to find the league week in a schedule file that contains [date] and the day of that week it falls on
+func LoadWeekForDate(path string, date time.Time) (int, int, error) {
+	weeks, _, err := readSeasonSchedule(path, LoadOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	return FindWeekForDate(weeks, date)
+}
+
+// Function to find the league week that contains [date] among the weeks of a season schedule and the day it falls on
+func FindWeekForDate(weeks map[string]WeekSchedule, date time.Time) (int, int, error) {
+	for key, week := range weeks {
+		day, ok := week.GetDay(date)
+		if !ok {
+			continue
+		}
+		number, err := strconv.Atoi(key)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid week %q in schedule", key)
+		}
+		return number, day, nil
+	}
+	return 0, 0, fmt.Errorf("no week in the schedule contains %s", date.Format(ISODateLayout))
+}
+
+// Function to parse a date from a request, like 2025-10-21
+func ParseISODate(value string) (time.Time, error) {
+	return time.Parse(ISODateLayout, value)
+}
+
+// Function to load consecutive weeks and stitch them into one schedule so streamers can be planned across week boundaries
+func LoadHorizonSchedule(path string, weeks []int) (WeekSchedule, error) {
+	return LoadHorizonScheduleWithOptions(path, weeks, LoadOptions{})
+}
+
+// Function to load consecutive weeks with the given options and stitch them into one schedule
+func LoadHorizonScheduleWithOptions(path string, weeks []int, options LoadOptions) (WeekSchedule, error) {
+	fullSchedule, errs, err := readSeasonSchedule(path, options)
+	if err != nil {
+		return WeekSchedule{}, err
+	}
+
+	schedule, err := SelectHorizon(fullSchedule, errs, weeks, options)
+	if err != nil {
+		fmt.Printf("Error loading schedule for weeks %v:\n%v\n", weeks, err)
+		return WeekSchedule{}, err
+	}
+
+	fmt.Printf("Loaded schedule for weeks %v\n", weeks)
+	return schedule, nil
+}
+
+// Function to pick consecutive weeks out of a season's schedule and stitch them into one schedule
+func SelectHorizon(weeks map[string]WeekSchedule, errs ScheduleErrors, numbers []int, options LoadOptions) (WeekSchedule, error) {
+	if len(numbers) == 0 {
+		return WeekSchedule{}, fmt.Errorf("no weeks given")
+	}
+
+	schedules := make([]WeekSchedule, len(numbers))
+	for i, week := range numbers {
+		if i > 0 && week != numbers[i-1]+1 {
+			return WeekSchedule{}, fmt.Errorf("weeks must be consecutive, got %d after %d", week, numbers[i-1])
+		}
+		schedule, err := SelectWeek(weeks, errs, week, options)
+		if err != nil {
+			return WeekSchedule{}, err
+		}
+		schedules[i] = schedule
+	}
+
+	return StitchWeekSchedules(numbers, schedules), nil
+}
+
+// Function to join week schedules into one day index, each week's days follow on from the previous week's
+func StitchWeekSchedules(weeks []int, schedules []WeekSchedule) WeekSchedule {
+	stitched := WeekSchedule{
+		TeamSchedules: make(map[string][]int),
+		Games:         make(map[string][]Game),
+		Weeks:         append([]int{}, weeks...),
+		WeekStarts:    make([]int, len(schedules)),
+	}
+	for i, schedule := range schedules {
+		if i == 0 {
+			stitched.StartDate = schedule.StartDate
+		}
+		stitched.EndDate = schedule.EndDate
+		stitched.WeekStarts[i] = stitched.GameSpan
+
+		for team, days := range schedule.TeamSchedules {
+			for _, day := range days {
+				stitched.TeamSchedules[team] = append(stitched.TeamSchedules[team], stitched.GameSpan+day)
+			}
+		}
+		for team, games := range schedule.Games {
+			for _, game := range games {
+				game.Day += stitched.GameSpan
+				stitched.Games[team] = append(stitched.Games[team], game)
+			}
+		}
+		stitched.GameSpan += schedule.GameSpan
+	}
+
+	return stitched
+}
+
+// Function to get the calendar date the schedule starts on
+func (w *WeekSchedule) GetStartTime() (time.Time, error) {
+	return time.Parse(WeekDateLayout, w.StartDate)
+}
+
+// Function to get the calendar date of [day], the zero time when the schedule has no valid start date
+func (w *WeekSchedule) GetDate(day int) time.Time {
+	start, err := w.GetStartTime()
+	if err != nil {
+		return time.Time{}
+	}
+	return start.AddDate(0, 0, day)
+}
+
+// Function to get the date of [day] in the response format, empty when the schedule has no valid start date
+func (w *WeekSchedule) GetISODate(day int) string {
+	date := w.GetDate(day)
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(ISODateLayout)
+}
+
+// Function to get the day index of [date], false when the date is outside the schedule
+func (w *WeekSchedule) GetDay(date time.Time) (int, bool) {
+	start, err := w.GetStartTime()
+	if err != nil {
+		return 0, false
+	}
+	calendar_date := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	day := int(calendar_date.Sub(start).Hours() / 24)
+	return day, !calendar_date.Before(start) && day < w.GameSpan
+}
+
+// Function to get the first day and the day after the last day of league week [week], false when the schedule doesn't have it
+func (w *WeekSchedule) GetWeekDays(week int) (int, int, bool) {
+	for i, number := range w.Weeks {
+		if number == week {
+			start, end := w.GetWeekBounds(w.GetWeekStarts()[i])
+			return start, end, true
+		}
+	}
+	return 0, 0, false
+}
+
 func (w *WeekSchedule) GetGameSpan() int {
 	return w.GameSpan
 }
@@ -61,6 +267,11 @@ func (w *WeekSchedule) GetTeamSchedule(team string) []int {
 	return w.TeamSchedules[team]
 }
 
+// Function to get the details of a team's games this week, empty when the schedule file has none
+func (w *WeekSchedule) GetGames(team string) []Game {
+	return w.Games[team]
+}
+
 func (w *WeekSchedule) IsPlaying(day int, team string) bool {
 	if game_list, ok := w.TeamSchedules[team]; ok && slices.Contains(game_list, day) {
 		return true
@@ -68,4 +279,79 @@ func (w *WeekSchedule) IsPlaying(day int, team string) bool {
 		return false
 	}
 }
-	
\ No newline at end of file
+	
+// Function to get the day each week starts on, a single week starts on day 0
+func (w *WeekSchedule) GetWeekStarts() []int {
+	if len(w.WeekStarts) == 0 {
+		return []int{0}
+	}
+	return w.WeekStarts
+}
+
+// Function to get which week of the schedule [day] falls in, counting from 0
+func (w *WeekSchedule) GetWeekIndex(day int) int {
+	index := 0
+	for i, start := range w.GetWeekStarts() {
+		if start <= day {
+			index = i
+		}
+	}
+	return index
+}
+
+// Function to get the first day and the day after the last day of the week that [day] falls in
+func (w *WeekSchedule) GetWeekBounds(day int) (int, int) {
+	starts := w.GetWeekStarts()
+	index := w.GetWeekIndex(day)
+	if index+1 < len(starts) {
+		return starts[index], starts[index+1]
+	}
+	return starts[index], w.GameSpan
+}
+
+// Function to count the games a team plays in the week that [day] falls in
+func (w *WeekSchedule) GetGamesInWeek(team string, day int) int {
+	start, end := w.GetWeekBounds(day)
+	games := 0
+	for _, game := range w.GetTeamSchedule(team) {
+		if game >= start && game < end {
+			games++
+		}
+	}
+	return games
+}
+
+// Function to get a player's expected points for his game on [day], weighted by his chance of playing. The date is only
+// needed for projections
+func (w *WeekSchedule) GetPoints(player Player, day int) float64 {
+	points := player.AvgPoints
+	if len(player.Projections) > 0 {
+		points = player.GetProjectedPoints(day, w.GetISODate(day))
+	}
+	return points * player.PlayProbability(day)
+}
+
+// Function to check if a player is expected to sit out his team's game on [day], like the second night of a
+// back-to-back or a game he's injured for
+func (w *WeekSchedule) IsResting(player Player, day int) bool {
+	if player.PlayProbability(day) == 0 {
+		return true
+	}
+	if len(player.Projections) == 0 {
+		return false
+	}
+	points, ok := player.GetProjection(day, w.GetISODate(day))
+	return ok && points <= 0
+}
+
+// Function to get a player's expected points over every game his team plays in the week that [day] falls in
+func (w *WeekSchedule) WeeklyPoints(player Player, day int) float64 {
+	start, end := w.GetWeekBounds(day)
+	total := 0.0
+	for _, game := range w.GetTeamSchedule(player.Team) {
+		if game >= start && game < end {
+			total += w.GetPoints(player, game)
+		}
+	}
+	return total
+}
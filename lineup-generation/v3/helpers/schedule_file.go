@@ -0,0 +1,149 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Version of the schedule file format with per game details. Files without a version are one of the legacy layouts
+const ScheduleVersion = 1
+
+// Versioned schedule file shared by every version of the lineup generator, v2/data/schedule_file.go reads the same
+// format into a season schedule
+type ScheduleFile struct {
+	Version int                     `json:"version"`
+	Season  string                  `json:"season,omitempty"`
+	Weeks   map[string]ScheduleWeek `json:"weeks"`
+}
+
+// One week of the versioned schedule file, games are listed per team
+type ScheduleWeek struct {
+	StartDate string            `json:"startDate"`
+	EndDate   string            `json:"endDate"`
+	GameSpan  int               `json:"gameSpan"`
+	Games     map[string][]Game `json:"games"`
+}
+
+// Single game from one team's side. Legacy files only know the day, so the other details can be empty and [Home] is nil
+// when it isn't known whether the team plays at home
+type Game struct {
+	Day      int    `json:"day"`
+	Opponent string `json:"opponent,omitempty"`
+	Home     *bool  `json:"home,omitempty"`
+	TipOff   string `json:"tipoff,omitempty"`
+}
+
+// Function to parse a schedule file in the versioned format or either legacy layout into week schedules keyed by week
+func ParseSeasonSchedule(data []byte) (map[string]WeekSchedule, error) {
+
+	var header struct {
+		Version  *int            `json:"version"`
+		Schedule json.RawMessage `json:"schedule"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case header.Version != nil:
+		if *header.Version != ScheduleVersion {
+			return nil, fmt.Errorf("unsupported schedule version %d", *header.Version)
+		}
+		var file ScheduleFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+		return file.ToWeekSchedules(), nil
+
+	case header.Schedule != nil:
+		// Legacy v2 layout, days are the keys of a map under a top level "schedule" key
+		var legacy map[string]struct {
+			StartDate     string                     `json:"startDate"`
+			EndDate       string                     `json:"endDate"`
+			GameSpan      int                        `json:"gameSpan"`
+			TeamSchedules map[string]map[string]bool `json:"games"`
+		}
+		if err := json.Unmarshal(header.Schedule, &legacy); err != nil {
+			return nil, err
+		}
+		weeks := make(map[string]WeekSchedule, len(legacy))
+		for key, week := range legacy {
+			team_schedules := make(map[string][]int, len(week.TeamSchedules))
+			for team, days := range week.TeamSchedules {
+				for day, playing := range days {
+					value, err := strconv.Atoi(day)
+					if err != nil {
+						return nil, fmt.Errorf("week %s: invalid day %q for %s", key, day, team)
+					}
+					if playing {
+						team_schedules[team] = append(team_schedules[team], value)
+					}
+				}
+				sort.Ints(team_schedules[team])
+			}
+			weeks[key] = WeekSchedule{StartDate: week.StartDate, EndDate: week.EndDate, GameSpan: week.GameSpan, TeamSchedules: team_schedules}
+		}
+		return weeks, nil
+
+	default:
+		// Legacy v3 layout, weeks at the top level with a list of days per team
+		var weeks map[string]WeekSchedule
+		if err := json.Unmarshal(data, &weeks); err != nil {
+			return nil, err
+		}
+		return weeks, nil
+	}
+}
+
+// Function to turn the versioned file into week schedules, keeping the game details alongside the days
+func (f *ScheduleFile) ToWeekSchedules() map[string]WeekSchedule {
+	weeks := make(map[string]WeekSchedule, len(f.Weeks))
+	for key, week := range f.Weeks {
+		schedule := WeekSchedule{
+			StartDate:     week.StartDate,
+			EndDate:       week.EndDate,
+			GameSpan:      week.GameSpan,
+			TeamSchedules: make(map[string][]int, len(week.Games)),
+			Games:         make(map[string][]Game, len(week.Games)),
+		}
+		for team, games := range week.Games {
+			for _, game := range games {
+				schedule.TeamSchedules[team] = append(schedule.TeamSchedules[team], game.Day)
+			}
+			sort.Ints(schedule.TeamSchedules[team])
+			schedule.Games[team] = append([]Game{}, games...)
+			sort.SliceStable(schedule.Games[team], func(i, j int) bool {
+				return schedule.Games[team][i].Day < schedule.Games[team][j].Day
+			})
+		}
+		weeks[key] = schedule
+	}
+	return weeks
+}
+
+// Function to build a versioned file from week schedules, teams without game details get a game for each day they play
+func NewScheduleFile(season string, weeks map[string]WeekSchedule) ScheduleFile {
+	file := ScheduleFile{Version: ScheduleVersion, Season: season, Weeks: make(map[string]ScheduleWeek, len(weeks))}
+	for key, week := range weeks {
+		schedule_week := ScheduleWeek{
+			StartDate: week.StartDate,
+			EndDate:   week.EndDate,
+			GameSpan:  week.GameSpan,
+			Games:     make(map[string][]Game, len(week.TeamSchedules)),
+		}
+		for team, days := range week.TeamSchedules {
+			games := week.GetGames(team)
+			if games == nil || len(games) != len(days) {
+				games = make([]Game, len(days))
+				for i, day := range days {
+					games[i] = Game{Day: day}
+				}
+			}
+			schedule_week.Games[team] = games
+		}
+		file.Weeks[key] = schedule_week
+	}
+	return file
+}
@@ -0,0 +1,227 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pattern of the schedule files the registry loads from its directory
+const ScheduleFilePattern = "schedule*.json"
+
+// How often the registry checks its directory for changed schedule files by default
+const DefaultReloadInterval = 30 * time.Second
+
+// Schedules for every season in a directory, reloaded when the files change so a new schedule doesn't need a restart
+// v2/data/schedule_registry.go is the same registry keeping a season schedule instead of week schedules, a change
+// to one usually belongs in both
+type ScheduleRegistry struct {
+	dir          string
+	reload_mutex sync.Mutex
+	mutex        sync.RWMutex
+	seasons      map[string]*registeredSchedule
+}
+
+// One season's schedule file as it was last loaded, problems are found once when the file is loaded
+type registeredSchedule struct {
+	season   string
+	path     string
+	mod_time time.Time
+	size     int64
+	weeks    map[string]WeekSchedule
+	errors   ScheduleErrors
+}
+
+// Function to create a registry with every schedule file in [dir]
+func NewScheduleRegistry(dir string) (*ScheduleRegistry, error) {
+	registry := &ScheduleRegistry{dir: dir, seasons: make(map[string]*registeredSchedule)}
+	if err := registry.Reload(); err != nil {
+		return nil, err
+	}
+	if len(registry.Seasons()) == 0 {
+		return nil, fmt.Errorf("no schedules found in %s", dir)
+	}
+	return registry, nil
+}
+
+// Function to load the schedule files that are new or changed since the last load and forget the ones that are gone.
+// A file that can't be read keeps its season's previous schedule until it changes again
+func (r *ScheduleRegistry) Reload() error {
+	r.reload_mutex.Lock()
+	defer r.reload_mutex.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(r.dir, ScheduleFilePattern))
+	if err != nil {
+		return err
+	}
+
+	r.mutex.RLock()
+	previous := make(map[string]*registeredSchedule, len(r.seasons))
+	for _, schedule := range r.seasons {
+		previous[schedule.path] = schedule
+	}
+	r.mutex.RUnlock()
+
+	seasons := make(map[string]*registeredSchedule, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Printf("Error reading schedule %s: %v\n", path, err)
+			continue
+		}
+
+		schedule, loaded := previous[path]
+		if !loaded || !schedule.mod_time.Equal(info.ModTime()) || schedule.size != info.Size() {
+			next, err := loadRegisteredSchedule(path, info)
+			switch {
+			case err == nil:
+				fmt.Printf("Loaded schedule for season %s from %s\n", next.season, path)
+				schedule = next
+			case loaded:
+				fmt.Printf("Error reloading schedule %s, keeping the previous one: %v\n", path, err)
+				kept := *schedule
+				kept.mod_time = info.ModTime()
+				kept.size = info.Size()
+				schedule = &kept
+			default:
+				fmt.Printf("Error loading schedule %s: %v\n", path, err)
+				continue
+			}
+		}
+
+		// Paths are sorted, so the first file for a season wins
+		if other, ok := seasons[schedule.season]; ok {
+			fmt.Printf("Schedule %s is for season %s like %s, skipping it\n", path, schedule.season, other.path)
+			continue
+		}
+		seasons[schedule.season] = schedule
+	}
+
+	r.mutex.Lock()
+	r.seasons = seasons
+	r.mutex.Unlock()
+	return nil
+}
+
+// Function to read a schedule file and find the season it's for, legacy files without a season go by their file name
+func loadRegisteredSchedule(path string, info os.FileInfo) (*registeredSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	weeks, err := ParseSeasonSchedule(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var header struct {
+		Season string `json:"season"`
+	}
+	json.Unmarshal(data, &header)
+	season := header.Season
+	if season == "" {
+		season = strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "schedule"), ".json")
+	}
+
+	return &registeredSchedule{
+		season:   season,
+		path:     path,
+		mod_time: info.ModTime(),
+		size:     info.Size(),
+		weeks:    weeks,
+		errors:   ValidateSchedule(data),
+	}, nil
+}
+
+// Function to reload the schedules every [interval] until [stop] is closed
+func (r *ScheduleRegistry) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := r.Reload(); err != nil {
+				fmt.Println("Error reloading schedules:", err)
+			}
+		}
+	}
+}
+
+// Function to get the seasons the registry has schedules for, oldest first
+func (r *ScheduleRegistry) Seasons() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	seasons := make([]string, 0, len(r.seasons))
+	for season := range r.seasons {
+		seasons = append(seasons, season)
+	}
+	sort.Strings(seasons)
+	return seasons
+}
+
+// Function to get the season used when a request doesn't name one, the latest season
+func (r *ScheduleRegistry) DefaultSeason() string {
+	seasons := r.Seasons()
+	if len(seasons) == 0 {
+		return ""
+	}
+	return seasons[len(seasons)-1]
+}
+
+// Function to check if the registry has a schedule for [season]
+func (r *ScheduleRegistry) HasSeason(season string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	_, ok := r.seasons[season]
+	return ok
+}
+
+// Function to get a season's schedule, the default season is used when [season] is empty
+func (r *ScheduleRegistry) get(season string) (*registeredSchedule, error) {
+	if season == "" {
+		season = r.DefaultSeason()
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	schedule, ok := r.seasons[season]
+	if !ok {
+		return nil, fmt.Errorf("no schedule for season %q", season)
+	}
+	return schedule, nil
+}
+
+// Function to get consecutive weeks of a season's schedule stitched into one schedule
+func (r *ScheduleRegistry) LoadHorizon(season string, weeks []int, options LoadOptions) (WeekSchedule, error) {
+	schedule, err := r.get(season)
+	if err != nil {
+		return WeekSchedule{}, err
+	}
+	return SelectHorizon(schedule.weeks, schedule.errors, weeks, options)
+}
+
+// Function to find the week of a season that contains [date] and the day of that week it falls on
+func (r *ScheduleRegistry) FindWeekForDate(season string, date time.Time) (int, int, error) {
+	schedule, err := r.get(season)
+	if err != nil {
+		return 0, 0, err
+	}
+	return FindWeekForDate(schedule.weeks, date)
+}
+
+// Function to get the problems found in a season's schedule file when it was loaded
+func (r *ScheduleRegistry) GetErrors(season string) (ScheduleErrors, error) {
+	schedule, err := r.get(season)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.errors, nil
+}
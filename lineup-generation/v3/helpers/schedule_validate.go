@@ -0,0 +1,451 @@
+// Copy of v2/data/schedule_validate.go, only the package name differs. Each version is its own module, so the copies are
+// kept in sync by TestSharedFilesInSync in v3/tests/shared_files_test.go
+
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout of the week dates in schedule files, single digit months and days are accepted as well
+const WeekDateLayout = "1/2/2006"
+
+// Kind of problem found in a schedule file
+type ScheduleErrorKind string
+
+const (
+	InvalidJSON      ScheduleErrorKind = "invalid_json"
+	InvalidWeek      ScheduleErrorKind = "invalid_week"
+	InvalidDate      ScheduleErrorKind = "invalid_date"
+	GameSpanMismatch ScheduleErrorKind = "game_span_mismatch"
+	DayOutOfRange    ScheduleErrorKind = "day_out_of_range"
+	DuplicateDay     ScheduleErrorKind = "duplicate_day"
+	MissingTeam      ScheduleErrorKind = "missing_team"
+	OverlappingWeeks ScheduleErrorKind = "overlapping_weeks"
+	DuplicateKey     ScheduleErrorKind = "duplicate_key"
+)
+
+// Function that returns whether a kind of problem is only a warning. A team missing from a week is usually a team
+// without games that week, like over the All-Star break, so the schedule can still be used
+func (k ScheduleErrorKind) IsWarning() bool {
+	return k == MissingTeam
+}
+
+// Single problem in a schedule file, anchored to the line of the week or team it was found in
+type ScheduleError struct {
+	Kind    ScheduleErrorKind `json:"kind"`
+	Warning bool              `json:"warning,omitempty"`
+	Line    int               `json:"line"`
+	Week    string            `json:"week,omitempty"`
+	Team    string            `json:"team,omitempty"`
+	Day     int               `json:"day,omitempty"`
+	Message string            `json:"message"`
+}
+
+func (e ScheduleError) Error() string {
+	if e.Week == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d: week %s: %s", e.Line, e.Week, e.Message)
+}
+
+// Every problem found in a schedule file, ordered by line
+type ScheduleErrors []ScheduleError
+
+func (e ScheduleErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Function to get the errors as an error, nil when the schedule is valid. Warnings are left out since they don't make
+// a schedule invalid
+func (e ScheduleErrors) Err() error {
+	var errs ScheduleErrors
+	for _, err := range e {
+		if !err.Warning {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Function to keep the errors that affect the given weeks, errors that aren't tied to a week affect every week
+func (e ScheduleErrors) ForWeeks(weeks ...int) ScheduleErrors {
+	var errs ScheduleErrors
+	for _, err := range e {
+		if err.Week == "" {
+			errs = append(errs, err)
+			continue
+		}
+		for _, week := range weeks {
+			if err.Week == strconv.Itoa(week) {
+				errs = append(errs, err)
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// One week of a schedule file as written, a team listed twice on a day is kept so it can be reported
+type validationWeek struct {
+	start_date string
+	end_date   string
+	game_span  int
+	days       map[string][]int
+}
+
+// Function to check a schedule file in the versioned format or either legacy layout for problems that would quietly
+// produce wrong lineups: keys listed twice, teams listed twice on a day, days outside the week, game spans that don't
+// match the dates, teams missing from a week and weeks that overlap. Teams missing from a week are only warnings
+func ValidateSchedule(data []byte) ScheduleErrors {
+	lines := newLineIndex(data)
+
+	weeks, prefix, err := readValidationWeeks(data)
+	if err != nil {
+		return ScheduleErrors{lines.jsonError(err)}
+	}
+	key_lines, duplicate_keys := lines.keyLines(data)
+	week_line := func(week string) int {
+		return key_lines[joinPath(prefix, week)]
+	}
+	team_line := func(week string, team string) int {
+		return key_lines[joinPath(prefix, week, "games", team)]
+	}
+
+	// Weeks are checked in order so overlaps are found between neighbours
+	keys := make([]string, 0, len(weeks))
+	for key := range weeks {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, a_err := strconv.Atoi(keys[i])
+		b, b_err := strconv.Atoi(keys[j])
+		if a_err != nil || b_err != nil {
+			return keys[i] < keys[j]
+		}
+		return a < b
+	})
+
+	// Every team that plays in the season is expected in every week
+	all_teams := make(map[string]bool)
+	for _, week := range weeks {
+		for team := range week.days {
+			all_teams[team] = true
+		}
+	}
+
+	// Only the last of a key listed twice is read, so whatever the others held is quietly lost
+	var errs ScheduleErrors
+	for _, duplicate := range duplicate_keys {
+		errs = append(errs, ScheduleError{Kind: DuplicateKey, Line: duplicate.line, Week: weekOfPath(weeks, prefix, duplicate.path), Message: fmt.Sprintf("key %q is listed twice", duplicate.path)})
+	}
+
+	var previous_key string
+	var previous_end time.Time
+	for _, key := range keys {
+		week := weeks[key]
+		if _, err := strconv.Atoi(key); err != nil {
+			errs = append(errs, ScheduleError{Kind: InvalidWeek, Line: week_line(key), Week: key, Message: "week key is not a number"})
+		}
+
+		// The game span has to cover the dates of the week
+		start, start_err := time.Parse(WeekDateLayout, week.start_date)
+		end, end_err := time.Parse(WeekDateLayout, week.end_date)
+		switch {
+		case start_err != nil:
+			errs = append(errs, ScheduleError{Kind: InvalidDate, Line: week_line(key), Week: key, Message: fmt.Sprintf("invalid start date %q", week.start_date)})
+		case end_err != nil:
+			errs = append(errs, ScheduleError{Kind: InvalidDate, Line: week_line(key), Week: key, Message: fmt.Sprintf("invalid end date %q", week.end_date)})
+		case end.Before(start):
+			errs = append(errs, ScheduleError{Kind: InvalidDate, Line: week_line(key), Week: key, Message: fmt.Sprintf("end date %s is before start date %s", week.end_date, week.start_date)})
+		default:
+			if span := int(end.Sub(start).Hours()/24) + 1; span != week.game_span {
+				errs = append(errs, ScheduleError{Kind: GameSpanMismatch, Line: week_line(key), Week: key, Message: fmt.Sprintf("gameSpan is %d but %s to %s is %d days", week.game_span, week.start_date, week.end_date, span)})
+			}
+			if previous_key != "" && !start.After(previous_end) {
+				errs = append(errs, ScheduleError{Kind: OverlappingWeeks, Line: week_line(key), Week: key, Message: fmt.Sprintf("starts on %s before week %s ends", week.start_date, previous_key)})
+			}
+			previous_key = key
+			previous_end = end
+		}
+
+		// Every day a team plays has to be inside the week, and only once
+		teams := make([]string, 0, len(week.days))
+		for team := range week.days {
+			teams = append(teams, team)
+		}
+		sort.Strings(teams)
+		for _, team := range teams {
+			seen := make(map[int]bool, len(week.days[team]))
+			for _, day := range week.days[team] {
+				if day < 0 || day >= week.game_span {
+					errs = append(errs, ScheduleError{Kind: DayOutOfRange, Line: team_line(key, team), Week: key, Team: team, Day: day, Message: fmt.Sprintf("%s plays on day %d outside 0..%d", team, day, week.game_span-1)})
+				} else if seen[day] {
+					errs = append(errs, ScheduleError{Kind: DuplicateDay, Line: team_line(key, team), Week: key, Team: team, Day: day, Message: fmt.Sprintf("%s is listed twice on day %d", team, day)})
+				}
+				seen[day] = true
+			}
+		}
+
+		// A team without an entry would never be started that week
+		missing := make([]string, 0)
+		for team := range all_teams {
+			if _, ok := week.days[team]; !ok {
+				missing = append(missing, team)
+			}
+		}
+		sort.Strings(missing)
+		for _, team := range missing {
+			errs = append(errs, ScheduleError{Kind: MissingTeam, Warning: MissingTeam.IsWarning(), Line: week_line(key), Week: key, Team: team, Message: fmt.Sprintf("%s has no games listed", team)})
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Line < errs[j].Line
+	})
+	return errs
+}
+
+// Function to get the week a key path is in, empty when it isn't inside a week
+func weekOfPath(weeks map[string]validationWeek, prefix string, path string) string {
+	if prefix != "" {
+		rest, ok := strings.CutPrefix(path, prefix+"/")
+		if !ok {
+			return ""
+		}
+		path = rest
+	}
+	week, _, _ := strings.Cut(path, "/")
+	if _, ok := weeks[week]; !ok {
+		return ""
+	}
+	return week
+}
+
+// Function to read the weeks of a schedule file without merging duplicate days, along with the path the weeks are under
+func readValidationWeeks(data []byte) (map[string]validationWeek, string, error) {
+	var header struct {
+		Version  *int            `json:"version"`
+		Schedule json.RawMessage `json:"schedule"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, "", err
+	}
+
+	weeks := make(map[string]validationWeek)
+	switch {
+	case header.Version != nil:
+		if *header.Version != ScheduleVersion {
+			return nil, "", fmt.Errorf("unsupported schedule version %d", *header.Version)
+		}
+		var file ScheduleFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, "", err
+		}
+		for key, week := range file.Weeks {
+			days := make(map[string][]int, len(week.Games))
+			for team, games := range week.Games {
+				days[team] = make([]int, len(games))
+				for i, game := range games {
+					days[team][i] = game.Day
+				}
+			}
+			weeks[key] = validationWeek{start_date: week.StartDate, end_date: week.EndDate, game_span: week.GameSpan, days: days}
+		}
+		return weeks, "weeks", nil
+
+	case header.Schedule != nil:
+		var file struct {
+			Schedule map[string]struct {
+				StartDate     string                     `json:"startDate"`
+				EndDate       string                     `json:"endDate"`
+				GameSpan      int                        `json:"gameSpan"`
+				TeamSchedules map[string]map[string]bool `json:"games"`
+			} `json:"schedule"`
+		}
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, "", err
+		}
+		for key, week := range file.Schedule {
+			days := make(map[string][]int, len(week.TeamSchedules))
+			for team, playing := range week.TeamSchedules {
+				days[team] = []int{}
+				for day, plays := range playing {
+					value, err := strconv.Atoi(day)
+					if err != nil {
+						return nil, "", fmt.Errorf("week %s: invalid day %q for %s", key, day, team)
+					}
+					if plays {
+						days[team] = append(days[team], value)
+					}
+				}
+				sort.Ints(days[team])
+			}
+			weeks[key] = validationWeek{start_date: week.StartDate, end_date: week.EndDate, game_span: week.GameSpan, days: days}
+		}
+		return weeks, "schedule", nil
+
+	default:
+		var file map[string]struct {
+			StartDate     string           `json:"startDate"`
+			EndDate       string           `json:"endDate"`
+			GameSpan      int              `json:"gameSpan"`
+			TeamSchedules map[string][]int `json:"games"`
+		}
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, "", err
+		}
+		for key, week := range file {
+			weeks[key] = validationWeek{start_date: week.StartDate, end_date: week.EndDate, game_span: week.GameSpan, days: week.TeamSchedules}
+		}
+		return weeks, "", nil
+	}
+}
+
+// Offsets of the line breaks in a file, used to turn byte offsets into line numbers
+type lineIndex []int
+
+func newLineIndex(data []byte) lineIndex {
+	index := lineIndex{}
+	for i, b := range data {
+		if b == '\n' {
+			index = append(index, i)
+		}
+	}
+	return index
+}
+
+// Function to get the 1-based line of a byte offset
+func (l lineIndex) line(offset int64) int {
+	return sort.SearchInts(l, int(offset)) + 1
+}
+
+// Function to turn an error reading the file into a schedule error on the line it happened, line 0 when it has no offset
+func (l lineIndex) jsonError(err error) ScheduleError {
+	var syntax_err *json.SyntaxError
+	if errors.As(err, &syntax_err) {
+		return ScheduleError{Kind: InvalidJSON, Line: l.line(syntax_err.Offset - 1), Message: err.Error()}
+	}
+	var type_err *json.UnmarshalTypeError
+	if errors.As(err, &type_err) {
+		message := fmt.Sprintf("unexpected %s", type_err.Value)
+		if field := strings.TrimPrefix(type_err.Field, "."); field != "" {
+			message += " for " + field
+		}
+		return ScheduleError{Kind: InvalidJSON, Line: l.line(type_err.Offset - 1), Message: message}
+	}
+	return ScheduleError{Kind: InvalidJSON, Message: err.Error()}
+}
+
+// Key that appears more than once in the same object, with the line of the later one
+type duplicateKey struct {
+	path string
+	line int
+}
+
+// Function to find the line of every object key in a JSON document, keyed by its path like "weeks/3/games/OKC", along
+// with the keys that appear twice in the same object
+func (l lineIndex) keyLines(data []byte) (map[string]int, []duplicateKey) {
+	type frame struct {
+		path     string
+		object   bool
+		want_key bool
+		key      string
+		index    int
+	}
+
+	lines := make(map[string]int)
+	var duplicates []duplicateKey
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var stack []frame
+
+	// Once a value is read the parent object waits for its next key, or the parent list moves to its next element
+	value_done := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := &stack[len(stack)-1]
+		if top.object {
+			top.want_key = true
+		} else {
+			top.index++
+		}
+	}
+	value_path := func() string {
+		if len(stack) == 0 {
+			return ""
+		}
+		top := stack[len(stack)-1]
+		if top.object {
+			return joinPath(top.path, top.key)
+		}
+		return joinPath(top.path, strconv.Itoa(top.index))
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return lines, duplicates
+		}
+
+		if len(stack) > 0 && stack[len(stack)-1].object && stack[len(stack)-1].want_key {
+			top := &stack[len(stack)-1]
+			if token == json.Delim('}') {
+				stack = stack[:len(stack)-1]
+				value_done()
+				continue
+			}
+			top.key, _ = token.(string)
+			top.want_key = false
+			path, line := joinPath(top.path, top.key), l.line(decoder.InputOffset()-1)
+			if _, ok := lines[path]; ok {
+				// Keys inside a value that was listed twice are only reported once, with the value
+				nested := false
+				for _, duplicate := range duplicates {
+					nested = nested || strings.HasPrefix(path, duplicate.path+"/")
+				}
+				if !nested {
+					duplicates = append(duplicates, duplicateKey{path: path, line: line})
+				}
+				continue
+			}
+			lines[path] = line
+			continue
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			stack = append(stack, frame{path: value_path(), object: token == json.Delim('{'), want_key: true})
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			value_done()
+		default:
+			value_done()
+		}
+	}
+}
+
+// Function to join the parts of a key path, skipping empty parts
+func joinPath(parts ...string) string {
+	path := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			path = append(path, part)
+		}
+	}
+	return strings.Join(path, "/")
+}
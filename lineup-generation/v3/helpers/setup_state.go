@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"fmt"
+	"math"
 	"sort"
 )
 
@@ -11,10 +12,15 @@ type SetupStateMetadata struct {
 	streamable_players []Player
 	optimal_slotting   map[int]map[string]Player
 	unused_positions   map[int]map[string]bool
+	template           RosterTemplate
+	slot_weights       map[string]int
+	slotting_mode      SlottingMode
+	lock_mode          LockMode
+	ir_moves           []IRMove
 }
 
 func (ssm *SetupStateMetadata) Print() {
-	position_order := []string{"PG", "SG", "SF", "PF", "G", "F", "C", "UT1", "UT2", "UT3", "BE1", "BE2", "BE3"}
+	position_order := ssm.template.SlotOrder()
 
 	for i := range len(ssm.optimal_slotting) {
 		lineup := ssm.optimal_slotting[i]
@@ -36,32 +42,40 @@ func (ssm *SetupStateMetadata) Print() {
 	}
 }
 
-func InitSetupState(schedule *WeekSchedule, roster []Player, free_agents []Player, threshold float64) *SetupStateMetadata {
+func InitSetupState(schedule *WeekSchedule, roster []Player, free_agents []Player, threshold float64, template RosterTemplate, mode SlottingMode, lock LockMode) *SetupStateMetadata {
 
 	ssm := &SetupStateMetadata{
 		roster: roster,
 		streamable_players: make([]Player, 0),
 		optimal_slotting: make(map[int]map[string]Player),
 		unused_positions: make(map[int]map[string]bool),
+		template: template,
+		slot_weights: template.Weights(),
+		lock_mode: lock,
 	}
-	ssm.OptimizeSlotting(schedule, threshold)
+	ssm.OptimizeSlotting(schedule, threshold, mode)
 
 	return ssm
 }
 
-// Finds available slots and players to experiment with on a roster when considering undroppable players and restrictive positions
-func (ssm *SetupStateMetadata) OptimizeSlotting(schedule *WeekSchedule, threshold float64) {
+// Finds available slots and players to experiment with on a roster when considering undroppable players and restrictive positions.
+// The mode decides whether the most restrictive slots or the most points come first
+func (ssm *SetupStateMetadata) OptimizeSlotting(schedule *WeekSchedule, threshold float64, mode SlottingMode) {
+
+	ssm.slotting_mode = mode
 
 	// Convert RosterMap to slices and abstract out IR spot. For the first day, passm all players to get_available_slots
 	var streamable_players []Player
 	var non_streamable_players []Player
 	for _, player := range ssm.roster {
 
-		if player.Injured {
+		// Players who are out for the whole schedule are left out, the others come back into the lineup on the day they return
+		if player.IsOutBetween(0, schedule.GetGameSpan()) {
 			continue
 		}
 
-		if player.AvgPoints > threshold {
+		// Players in IR take back a roster spot when they're activated, so they're slotted like the players worth keeping
+		if player.AvgPoints > threshold || player.OnIR {
 			non_streamable_players = append(non_streamable_players, player)
 		} else {
 			streamable_players = append(streamable_players, player)
@@ -75,8 +89,16 @@ func (ssm *SetupStateMetadata) OptimizeSlotting(schedule *WeekSchedule, threshol
 
 	return_table := make(map[int]map[string]Player)
 
-	// Fill return table and put extra IR players on bench
+	// Fill return table, injured players stay out of it until the day they return. With a weekly lock the same lineup is kept every day of the week
+	var weekly_lineup map[string]Player
 	for i := 0; i < schedule.GetGameSpan(); i++ {
+		if ssm.lock_mode.IsWeekly() {
+			if start, _ := schedule.GetWeekBounds(i); start == i {
+				weekly_lineup = ssm.MatchWeek(schedule, non_streamable_players, i)
+			}
+			return_table[i] = weekly_lineup
+			continue
+		}
 		return_table[i] = ssm.GetAvailableSlots(schedule, non_streamable_players, i)
 	}
 
@@ -85,6 +107,7 @@ func (ssm *SetupStateMetadata) OptimizeSlotting(schedule *WeekSchedule, threshol
 		return streamable_players[i].AvgPoints > streamable_players[j].AvgPoints
 	})
 	ssm.streamable_players = streamable_players
+	ssm.ir_moves = ssm.PlanInjuredReserve(schedule, threshold)
 	ssm.optimal_slotting = return_table
 	ssm.FindUnusedPositions()
 }
@@ -100,55 +123,97 @@ type FitPlayersContext struct {
 // Function to get available slots for a given day
 func (ssm *SetupStateMetadata) GetAvailableSlots(schedule *WeekSchedule, players []Player, day int) map[string]Player {
 
-	// Priority order of most restrictive positions to funnel streamers into flexible positions
-	position_order := []string{"PG", "SG", "SF", "PF", "G", "F", "C", "UT1", "UT2", "UT3", "BE1", "BE2", "BE3"}
-	
 	var playing []Player
 
 	for _, player := range players {
 
-		// Checks if the player is playing on the given day
-		if schedule.IsPlaying(day, player.Team){
+		// Checks if the player is playing on the given day and isn't projected to rest
+		if schedule.IsPlaying(day, player.Team) && !schedule.IsResting(player, day) {
 			playing = append(playing, player)
 		}
 	}
 
-	// Find most restrictive positions for players playing
-	optimal_slotting := func (playing []Player) map[string]Player {
+	return ssm.MatchDay(schedule, playing, day)
+}
 
-		sort.Slice(playing, func(i, j int) bool {
-			return len(playing[i].ValidPositions) < len(playing[j].ValidPositions)
-		})
+// Function to slot players as a maximum weight bipartite matching between players and slots. By default restrictiveness
+// comes first and ties are broken by putting the best players in starting slots, the points mode flips the two
+func (ssm *SetupStateMetadata) MatchPlayers(players []Player) map[string]Player {
+	return ssm.matchPlayers(players, func(player Player) float64 {
+		return player.AvgPoints
+	})
+}
 
-		// Create struct to keep track of state acrossm recursive function calls
-		p_context := &FitPlayersContext{
-			BestLineup: make(map[string]Player), 
-			TopScore: 0,
-			MaxScore: ssm.CalculateMaxScore(playing),
-			EarlyExit: false,
-		}
-	
-		// Recursive function call
-		ssm.FitPlayers(playing, make(map[string]Player), position_order, p_context, 0)
-	
-		// Create response map and fill with best lineup or empty strings for unused positions except for bench spots
-		response := make(map[string]Player)
-		for _, pos := range position_order {
+// Function to slot the players playing on [day], where each player is worth his projected points for that game
+func (ssm *SetupStateMetadata) MatchDay(schedule *WeekSchedule, players []Player, day int) map[string]Player {
+	return ssm.matchPlayers(players, func(player Player) float64 {
+		return schedule.GetPoints(player, day)
+	})
+}
 
-			if value, ok := p_context.BestLineup[pos]; ok {
-				response[pos] = value
+// Function to slot players into one lineup for the week that [day] falls in, where each player is worth his points over
+// every game he plays that week
+func (ssm *SetupStateMetadata) MatchWeek(schedule *WeekSchedule, players []Player, day int) map[string]Player {
+	return ssm.matchPlayers(players, func(player Player) float64 {
+		return schedule.WeeklyPoints(player, day)
+	})
+}
+
+// Function to build and solve the matching between players and slots, using [points] as the value of starting a player
+// v2/team/base_team.go builds the same matching for v2's players, a change here usually belongs there too
+func (ssm *SetupStateMetadata) matchPlayers(players []Player, points func(Player) float64) map[string]Player {
+
+	position_order := ssm.template.SlotOrder()
+
+	// Points are compared in hundredths so both objectives stack exactly, the mode decides which one comes first
+	total_points := 0.0
+	for _, player := range players {
+		total_points += math.Round(max(points(player), 0) * 100)
+	}
+	total_weight := 0.0
+	for _, pos := range position_order {
+		total_weight += float64(ssm.slot_weights[pos])
+	}
+
+	// Slot order lists the starting slots first, anything after them is a bench slot that takes anyone
+	starting := len(ssm.template.Slots)
+	by_points := ssm.slotting_mode == SlotByPoints
+	weights := make([][]float64, len(players))
+	for i, player := range players {
+		weights[i] = make([]float64, len(position_order))
+		for j, pos := range position_order {
+			if j < starting && !ssm.template.Accepts(pos, player) {
+				weights[i][j] = math.Inf(-1)
 				continue
 			}
-		}
 
-		return response
-	}(playing)
+			// Only players in starting slots score points
+			weight := float64(ssm.slot_weights[pos])
+			value := 0.0
+			if j < starting {
+				value = math.Round(max(points(player), 0) * 100)
+			}
 
-	return optimal_slotting
+			if by_points {
+				weights[i][j] = value*(total_weight+1) + weight
+			} else {
+				weights[i][j] = weight*(total_points+1) + value
+			}
+		}
+	}
+
+	lineup := make(map[string]Player)
+	for i, j := range MaxWeightAssignment(weights) {
+		if j >= 0 {
+			lineup[position_order[j]] = players[i]
+		}
+	}
 
+	return lineup
 }
 
-// Recursive backtracking function to find most restrictive positions for players
+// Recursive backtracking function to find most restrictive positions for players. Superseded by MatchPlayers
+// for daily slotting and kept as a reference for tests and benchmarks, like its twin in v2/team/base_team.go
 func (ssm *SetupStateMetadata) FitPlayers(players []Player, cur_lineup map[string]Player, position_order []string, ctx *FitPlayersContext, index int) {
 
 	// If we have found a lineup that has the max score, we can send returns to all other recursive calls
@@ -180,7 +245,7 @@ func (ssm *SetupStateMetadata) FitPlayers(players []Player, cur_lineup map[strin
 	position := position_order[index]
 	found_player := false
 	for _, player := range players {
-		if player.PlaysPosition(position) {
+		if ssm.template.Accepts(position, player) {
 			found_player = true
 			cur_lineup[position] = player
 
@@ -207,19 +272,10 @@ func (ssm *SetupStateMetadata) FitPlayers(players []Player, cur_lineup map[strin
 // Function to score a roster based on restricitvenessm of positions
 func (ssm *SetupStateMetadata) ScoreRoster(roster map[string]Player) int {
 
-	// Scoring system - hardcoded for performance
-	score_map := map[string]int{
-		"PG":  5, "SG":  5, "SF":  5, "PF":  5,  // Most restrictive positions
-		"G":   4, "F":   4,
-		"C":   3,
-		"UT1": 2, "UT2": 2, "UT3": 2,
-		"BE1": 1, "BE2": 1, "BE3": 1,            // Least restrictive positions
-	}
-
-	// Score roster
+	// Score roster using the template's slot weights
 	score := 0
 	for pos := range roster {
-		score += score_map[pos]
+		score += ssm.slot_weights[pos]
 	}
 
 	return score
@@ -228,27 +284,39 @@ func (ssm *SetupStateMetadata) ScoreRoster(roster map[string]Player) int {
 // Function to calculate the max restrictivenessm score for a given set of players
 func (ssm *SetupStateMetadata) CalculateMaxScore(players []Player) int {
 
-	size := len(players)
-
-	// Max score calulation corresponding with scoring_groups in score_roster
-	switch {
-	case size <= 4:
-		return size * 5
-	case size <= 6:
-		return 20 + ((size - 4) * 4)
-	case size <= 7:
-		return 28 + ((size - 6) * 3)
-	case size <= 10:
-		return 31 + ((size - 7) * 2)
-	default:
-		return 37 + (size - 10)
-}
+	position_order := ssm.template.SlotOrder()
+
+	// Each player can at best land in the highest weighted slot he is eligible for
+	player_bound := 0
+	for _, player := range players {
+		best := 0
+		for _, pos := range position_order {
+			if ssm.slot_weights[pos] > best && ssm.template.Accepts(pos, player) {
+				best = ssm.slot_weights[pos]
+			}
+		}
+		player_bound += best
+	}
+
+	// Each slot can only be used once, so at best the players fill the highest weighted slots
+	weights := make([]int, 0, len(position_order))
+	for _, pos := range position_order {
+		weights = append(weights, ssm.slot_weights[pos])
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(weights)))
+
+	slot_bound := 0
+	for i := 0; i < len(players) && i < len(weights); i++ {
+		slot_bound += weights[i]
+	}
+
+	return min(player_bound, slot_bound)
 }
 
 // Function to get the unused positions from the optimal slotting for good players playing for the week
 func (ssm *SetupStateMetadata) FindUnusedPositions() {
 
-	positions := []string{"PG", "SG", "SF", "PF", "C", "G", "F", "UT1", "UT2", "UT3"}
+	positions := ssm.template.StartingSlots()
 
 	// Create map to keep track of unused positions
 	unused_positions := make(map[int]map[string]bool)
@@ -284,4 +352,20 @@ func (ssm *SetupStateMetadata) GetOptimalSlotting() map[int]map[string]Player {
 
 func (ssm *SetupStateMetadata) GetUnusedPositions() map[int]map[string]bool {
 	return ssm.unused_positions
-}
\ No newline at end of file
+}
+
+func (ssm *SetupStateMetadata) GetTemplate() RosterTemplate {
+	return ssm.template
+}
+
+func (ssm *SetupStateMetadata) GetSlottingMode() SlottingMode {
+	return ssm.slotting_mode
+}
+
+func (ssm *SetupStateMetadata) GetLockMode() LockMode {
+	return ssm.lock_mode
+}
+
+func (ssm *SetupStateMetadata) GetIRMoves() []IRMove {
+	return ssm.ir_moves
+}
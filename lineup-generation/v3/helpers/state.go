@@ -1,6 +1,9 @@
 package helpers
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 
 type Lineup struct {
@@ -8,6 +11,7 @@ type Lineup struct {
 	bench 		[]Player
 	additions []Player
 	removals 	[]Player
+	actions   []RosterAction
 	score 		float64
 }
 
@@ -26,8 +30,7 @@ func NewLineup(unused_positions map[string]bool) Lineup {
 	return lineup
 }
 
-func (l *Lineup) Print() {
-	position_order := []string{"PG", "SG", "SF", "PF", "C", "G", "F", "UT1", "UT2", "UT3", "BE1", "BE2", "BE3"}
+func (l *Lineup) Print(position_order []string) {
 	for _, position := range position_order {
 		if player, ok := l.roster[position]; ok {
 			fmt.Println(position, player.Name, player.AvgPoints)
@@ -48,18 +51,19 @@ func (l *Lineup) GetBench() []Player {
 	return l.bench
 }
 
-func (l *Lineup) SlotStreamer(streamer Player) bool {
+// Function to slot a streamer into the first open position he can play, where he scores [points]
+func (l *Lineup) SlotStreamer(streamer Player, points float64, template *RosterTemplate) bool {
 
 	// Priority order of most restrictive positions to funnel streamers into flexible positions
-	position_order := []string{"PG", "SG", "SF", "PF", "G", "F", "C", "UT1", "UT2", "UT3", "BE1", "BE2", "BE3"}
+	position_order := template.StartingSlots()
 
 	found_position := false
 	for _, position := range position_order {
-		// If we position is 
-		if player, ok := l.roster[position]; ok && streamer.PlaysPosition(position) {
+		// If the position is open and the streamer can play it, slot him there
+		if player, ok := l.roster[position]; ok && player.Name == "" && template.Accepts(position, streamer) {
 			l.roster[position] = streamer
 			found_position = true
-			l.score += streamer.AvgPoints
+			l.score += points
 			break
 		}
 	}
@@ -69,12 +73,18 @@ func (l *Lineup) SlotStreamer(streamer Player) bool {
 
 type State struct {
 	day        	      int
+	start_day         int
 	score 		        int
 	acq_left    	    int
 	lineups      	    []Lineup
 	free_agents    	  []Player
 	current_streamers []Player
 	dropped_players 	[]DroppedPlayer
+	template          RosterTemplate
+	limits            AcquisitionLimits
+	lock_mode         LockMode
+	week_starts       []int
+	ir_moves          []IRMove
 }
 
 
@@ -85,21 +95,32 @@ func (s *State) Print() {
 	fmt.Println("--------------------------------")
 	for i := range len(s.lineups) {
 		fmt.Println("Day:", i)
-		s.lineups[i].Print()
+		s.lineups[i].Print(s.template.SlotOrder())
 	}
 	fmt.Println()
 }
 
 
-func InitState(schedule *WeekSchedule, ssm *SetupStateMetadata, free_agents []Player) *State {
+func InitState(schedule *WeekSchedule, ssm *SetupStateMetadata, free_agents []Player, limits AcquisitionLimits) *State {
+	return InitStateOnDay(schedule, ssm, free_agents, limits, 0)
+}
+
+// Function to create the root state for re-planning from [day] on. The earlier days are frozen, the streamers only
+// score from [day] on and the acquisitions used earlier in the week come from the limits
+func InitStateOnDay(schedule *WeekSchedule, ssm *SetupStateMetadata, free_agents []Player, limits AcquisitionLimits, day int) *State {
 	state := &State{
-		day: 0,
+		day: day,
+		start_day: day,
 		score: 0,
-		acq_left: schedule.GetGameSpan(),
+		acq_left: limits.WeeklyLimit(),
 		lineups: make([]Lineup, schedule.GetGameSpan()),
 		free_agents: make([]Player, 0),
 		current_streamers: make([]Player, 0),
 		dropped_players: make([]DroppedPlayer, 0),
+		template: ssm.template,
+		limits: limits,
+		lock_mode: ssm.lock_mode,
+		week_starts: schedule.GetWeekStarts(),
 	}
 	
 	// Initialize each lineup with proper structure
@@ -108,14 +129,30 @@ func InitState(schedule *WeekSchedule, ssm *SetupStateMetadata, free_agents []Pl
 	}
 	
 	// Set the current streamers and free agents
-	state.current_streamers = ssm.GetStreamablePlayers()
+	state.current_streamers = append([]Player{}, ssm.GetStreamablePlayers()...)
 	state.free_agents = free_agents
+
+	// Injured players move into IR on the first day of the plan, each one frees a roster spot for a streamer
+	for _, move := range ssm.ir_moves {
+		if move.ReturnDay < day || (move.MoveToIR && move.ReturnDay == day) {
+			continue
+		}
+		state.ir_moves = append(state.ir_moves, move)
+		if move.MoveToIR {
+			state.lineups[day].actions = append(state.lineups[day].actions, RosterAction{Action: ActionMoveToIR, Player: move.Player})
+			state.current_streamers = append(state.current_streamers, OpenRosterSpot())
+		}
+	}
+
 	// Slot the streamers into the lineup
 	state.SlotStreamers(schedule, false) // Don't decrement acq_left since these are players that are already on the roster
 
 	// Score the lineup
 	state.ScoreLineup()
 
+	// A player already in IR who is back on the first day takes his spot back right away
+	state.ActivateReturningPlayers(schedule, ssm)
+
 	// This now serves as the initial (root) state for the beam search algorithm
 	return state
 }
@@ -124,10 +161,25 @@ func InitState(schedule *WeekSchedule, ssm *SetupStateMetadata, free_agents []Pl
 func (s *State) SlotStreamers(schedule *WeekSchedule, decrement_acq_left bool) {
 	// Note: streamers are already sorted by average points
 
-	for _, streamer := range s.current_streamers {
+	if s.lock_mode.IsWeekly() {
+		s.LockStreamers(schedule)
+		if decrement_acq_left {
+			s.acq_left -= len(s.current_streamers)
+		}
+		return
+	}
+
+	// Streamers making room for players back from IR only play until then
+	until := s.GetReturnDrops()
+	for i, streamer := range s.current_streamers {
 		for _, day := range schedule.GetTeamSchedule(streamer.Team) {
-			if day >= s.day {
-				if ok := s.lineups[day].SlotStreamer(streamer); !ok {
+			if day >= s.day && day < until[i] {
+				// A streamer projected to rest sits on the bench so he doesn't take a slot from someone who plays
+				if schedule.IsResting(streamer, day) {
+					s.lineups[day].bench = append(s.lineups[day].bench, streamer)
+					continue
+				}
+				if ok := s.lineups[day].SlotStreamer(streamer, schedule.GetPoints(streamer, day), &s.template); !ok {
 					s.lineups[day].bench = append(s.lineups[day].bench, streamer)
 				}
 			}
@@ -139,6 +191,56 @@ func (s *State) SlotStreamers(schedule *WeekSchedule, decrement_acq_left bool) {
 	}
 }
 
+// Function to slot the streamers under a weekly lock for every week from the current day on
+func (s *State) LockStreamers(schedule *WeekSchedule) {
+	for start := s.day; start < len(s.lineups); {
+		_, end := schedule.GetWeekBounds(start)
+		s.LockWeek(schedule, start, end)
+		start = end
+	}
+}
+
+// Function to slot the streamers for the days [start, end) of a locked week. Each streamer keeps one open slot for the
+// whole week and only scores on the days he plays, so the streamers with the most games and points get first pick
+func (s *State) LockWeek(schedule *WeekSchedule, start int, end int) {
+
+	// Open roster spots don't take a slot and streamers making room for players back from IR leave when they return
+	until := make(map[string]int)
+	streamers := make([]Player, 0, len(s.current_streamers))
+	for i, day := range s.GetReturnDrops() {
+		if streamer := s.current_streamers[i]; !streamer.IsOpenRosterSpot() {
+			until[streamer.Name] = day
+			streamers = append(streamers, streamer)
+		}
+	}
+	sort.SliceStable(streamers, func(i, j int) bool {
+		return schedule.WeeklyPoints(streamers[i], start) > schedule.WeeklyPoints(streamers[j], start)
+	})
+
+	// Open slots are the same every day of a locked week, so the first day decides where each streamer goes
+	lineup := &s.lineups[start]
+	for _, streamer := range streamers {
+		slot := ""
+		for _, position := range s.template.StartingSlots() {
+			if player, ok := lineup.roster[position]; ok && player.Name == "" && s.template.Accepts(position, streamer) {
+				slot = position
+				break
+			}
+		}
+
+		for day := start; day < min(end, until[streamer.Name]); day++ {
+			if slot == "" {
+				s.lineups[day].bench = append(s.lineups[day].bench, streamer)
+				continue
+			}
+			s.lineups[day].roster[slot] = streamer
+			if schedule.IsPlaying(day, streamer.Team) {
+				s.lineups[day].score += schedule.GetPoints(streamer, day)
+			}
+		}
+	}
+}
+
 func (s *State) ScoreLineup() {
 	total_score := 0.0
 	for _, lineup := range s.lineups {
@@ -152,6 +254,10 @@ func (s *State) GetDay() int {
 	return s.day
 }
 
+func (s *State) GetStartDay() int {
+	return s.start_day
+}
+
 func (s *State) GetScore() int {
 	return s.score
 }
@@ -172,6 +278,14 @@ func (s *State) GetCurrentStreamers() []Player {
 	return s.current_streamers
 }
 
+func (s *State) GetLimits() AcquisitionLimits {
+	return s.limits
+}
+
+func (s *State) GetLockMode() LockMode {
+	return s.lock_mode
+}
+
 func (s *State) GetDroppedPlayers() []DroppedPlayer {
 	return s.dropped_players
 }
\ No newline at end of file
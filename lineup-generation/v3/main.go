@@ -1,15 +1,37 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"net/http"
+	"time"
 
 	h "v3/helpers"
 )
 
+// Returned by GenerateLineup when the current day isn't a day of the first week
+var errCurrentDayOutsideWeek = errors.New("current day is outside the week")
+
 func main() {
 
+	// Schedules for every season are loaded once and reloaded when their files change
+	schedule_dir := flag.String("schedules", "../static", "directory with a schedule file for each season, shared by every version")
+	reload_interval := flag.Duration("reload", h.DefaultReloadInterval, "how often to check the schedule files for changes")
+	default_season := flag.String("season", "", "season used when a request doesn't name one, the latest season by default")
+	flag.Parse()
+
+	registry, err := h.NewScheduleRegistry(*schedule_dir)
+	if err != nil {
+		panic(err)
+	}
+	if *default_season != "" && !registry.HasSeason(*default_season) {
+		panic(fmt.Sprintf("no schedule for season %q in %s", *default_season, *schedule_dir))
+	}
+	go registry.Watch(*reload_interval, nil)
+
 	fmt.Println("Server started on port 8080")
 
 	// Handle request
@@ -33,11 +55,88 @@ func main() {
 			return
 		}
 
+		// Make sure the roster template can be used before generating a lineup
+		if template := request.GetRosterTemplate(); template.Validate() != nil {
+			http.Error(w, "Invalid roster template: "+template.Validate().Error(), http.StatusBadRequest)
+			return
+		}
+		if err := request.GetSlottingMode().Validate(); err != nil {
+			http.Error(w, "Invalid slotting mode: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := request.GetLockMode().Validate(); err != nil {
+			http.Error(w, "Invalid lock mode: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := request.GetAcquisitionLimits(0).Validate(); err != nil {
+			http.Error(w, "Invalid acquisition limits: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := request.ValidateTimeBudget(); err != nil {
+			http.Error(w, "Invalid time budget: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.ValidateInjuries(append(append([]h.Player{}, request.RosterData...), request.FreeAgentData...)); err != nil {
+			http.Error(w, "Invalid injury status: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The request picks the season's schedule, the default season is used when it doesn't name one
+		if request.Season == "" {
+			request.Season = *default_season
+		}
+		if request.Season == "" {
+			request.Season = registry.DefaultSeason()
+		}
+		if !registry.HasSeason(request.Season) {
+			http.Error(w, "Unknown season: "+request.Season, http.StatusBadRequest)
+			return
+		}
+
+		// A start date picks the week that contains it, so a plan can be made from today without knowing the week
+		if request.StartDate != "" {
+			if len(request.Weeks) > 0 {
+				http.Error(w, "start_date can't be combined with weeks", http.StatusBadRequest)
+				return
+			}
+			date, err := h.ParseISODate(request.StartDate)
+			if err != nil {
+				http.Error(w, "Invalid start date: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			week, day, err := registry.FindWeekForDate(request.Season, date)
+			if err != nil {
+				http.Error(w, "Invalid start date: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			request.Week = week
+
+			// Planning from a date in the middle of the week starts on that day
+			if request.CurrentDay == nil {
+				request.CurrentDay = &day
+			}
+		}
+		if request.GetCurrentDay() < 0 {
+			http.Error(w, "Invalid current day: can't be negative", http.StatusBadRequest)
+			return
+		}
+
 		// Print the decoded request for debugging purposes
 		fmt.Printf("Received request: %+v\n", request)
 
+		// A schedule that can't be trusted for the requested weeks is an error rather than an empty lineup
+		response, err := GenerateLineup(r.Context(), registry, request)
+		if errors.Is(err, errCurrentDayOutsideWeek) {
+			http.Error(w, "Invalid current day: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to load schedule: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
 		// Respond with a JSON-encoded message
-		json_data, err := json.Marshal(GenerateLineup(request))
+		json_data, err := json.Marshal(response)
 		if err != nil {
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 			return
@@ -56,29 +155,53 @@ func main() {
 	}
 }
 
-func GenerateLineup(request h.Request) h.Response {
-	// Initialize the schedule for the specific week requested
-	schedule, err := h.LoadWeekSchedule("./static/schedule2025-2026.json", request.Week)
+func GenerateLineup(ctx context.Context, registry *h.ScheduleRegistry, request h.Request) (h.Response, error) {
+	start := time.Now()
+
+	// Initialize the schedule for the weeks requested, consecutive weeks are planned as one stretch of days
+	weeks := request.GetWeeks()
+	schedule, err := registry.LoadHorizon(request.Season, weeks, h.LoadOptions{RejectInvalid: true})
 	if err != nil {
-		fmt.Printf("Error loading schedule for week %d: %v\n", request.Week, err)
-		return h.Response{
-			Lineup:     []h.Roster{},
-			Improvement: 0,
-			Timestamp:  "",
-			Week:       request.Week,
-			Threshold:  request.Threshold,
-		}
+		fmt.Printf("Error loading schedule for weeks %v: %v\n", weeks, err)
+		return h.Response{}, err
 	}
 
-	setup_state := h.InitSetupState(&schedule, request.RosterData, request.FreeAgentData, request.Threshold)
+	setup_state := h.InitSetupState(&schedule, request.RosterData, request.FreeAgentData, request.Threshold, request.GetRosterTemplate(), request.GetSlottingMode(), request.GetLockMode())
+
+	// The root state is the roster with no moves made from the current day on, which is the baseline for the
+	// improvement. Every week gets the league's weekly budget, by default one acquisition for each day of the first week
+	_, first_week_end := schedule.GetWeekBounds(0)
+	current_day := request.GetCurrentDay()
+	if current_day >= first_week_end {
+		return h.Response{}, fmt.Errorf("%w: day %d of a %d day week", errCurrentDayOutsideWeek, current_day, first_week_end)
+	}
+	root_state := h.InitStateOnDay(&schedule, setup_state, request.FreeAgentData, request.GetAcquisitionLimits(first_week_end), current_day)
 
-	// TODO: Implement lineup generation logic using weekSchedule
-	// For now, return an empty response
+	// A time budget counted from the start of the request keeps widening the beam until it runs out, and a client that
+	// disconnects cuts the search off. Either way the best plan found so far is returned
+	var best_state *h.State
+	if budget := request.GetTimeBudget(); budget > 0 {
+		budget_ctx, cancel := context.WithTimeout(ctx, budget-time.Since(start))
+		defer cancel()
+		best_state, err = h.WideningBeamSearch(budget_ctx, &schedule, setup_state, root_state)
+	} else {
+		best_state, err = h.BeamSearchContext(ctx, &schedule, setup_state, root_state, h.BeamWidth)
+	}
+	if err != nil {
+		fmt.Println("Stopped searching early:", err)
+	}
+
+	// The lineup is the current week's plan from the current day on, the improvement counts the remaining games of every
+	// week in the horizon
+	plan := h.SplitByWeek(&schedule, weeks, best_state.ToRosters(setup_state))
 	return h.Response{
-		Lineup:     []h.Roster{},
-		Improvement: 0,
-		Timestamp:  "",
-		Week:       request.Week,
+		Lineup:     plan[0].Lineup,
+		Plan:       plan,
+		Improvement: best_state.GetScore() - root_state.GetScore(),
+		Timestamp:  time.Now().Format("1/2/2006 3:04PM"),
+		Week:       weeks[0],
 		Threshold:  request.Threshold,
-	}
+		Season:     request.Season,
+		Converged:  err == nil,
+	}, nil
 }
\ No newline at end of file
@@ -0,0 +1,243 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	h "v3/helpers"
+)
+
+// Layout of the dates in the week schedules and league config
+const DateLayout = "01/02/2006"
+
+// Layout of the game dates in the raw schedule
+const GameDateLayout = "01/02/2006 15:04:05"
+
+// First and last day of a fantasy week at midnight UTC
+type week struct {
+	start time.Time
+	end   time.Time
+}
+
+// Function to build the week schedules from a raw league schedule, keyed by fantasy week number starting at 1
+func Build(raw RawSchedule, config LeagueConfig) (map[string]h.WeekSchedule, error) {
+
+	weeks, err := config.FantasyWeeks(raw.LeagueSchedule.Weeks)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := make(map[string]h.WeekSchedule, len(weeks))
+	for i, w := range weeks {
+		schedule[strconv.Itoa(i+1)] = h.WeekSchedule{
+			StartDate:     w.start.Format(DateLayout),
+			EndDate:       w.end.Format(DateLayout),
+			GameSpan:      daysBetween(w.start, w.end) + 1,
+			TeamSchedules: make(map[string][]int),
+			Games:         make(map[string][]h.Game),
+		}
+	}
+
+	// Give both teams of every regular season game the day of the week it is played on
+	for _, game_date := range raw.LeagueSchedule.GameDates {
+		date, err := time.Parse(GameDateLayout, game_date.GameDate)
+		if err != nil {
+			return nil, fmt.Errorf("parsing game date %q: %w", game_date.GameDate, err)
+		}
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+		index := findWeek(weeks, date)
+		if index == -1 {
+			continue
+		}
+		day := daysBetween(weeks[index].start, date)
+		week_schedule := schedule[strconv.Itoa(index+1)]
+
+		for _, game := range game_date.Games {
+			if !strings.HasPrefix(game.GameId, RegularSeasonPrefix) {
+				continue
+			}
+			home, away := game.HomeTeam.TeamTricode, game.AwayTeam.TeamTricode
+			at_home, away_from_home := true, false
+			sides := []h.Game{
+				{Day: day, Opponent: away, Home: &at_home, TipOff: game.GameDateTimeUTC},
+				{Day: day, Opponent: home, Home: &away_from_home, TipOff: game.GameDateTimeUTC},
+			}
+			for i, team := range []string{home, away} {
+				if team != "" && !slices.Contains(week_schedule.TeamSchedules[team], day) {
+					week_schedule.TeamSchedules[team] = append(week_schedule.TeamSchedules[team], day)
+					week_schedule.Games[team] = append(week_schedule.Games[team], sides[i])
+				}
+			}
+		}
+	}
+
+	for _, week_schedule := range schedule {
+		for team, days := range week_schedule.TeamSchedules {
+			sort.Ints(days)
+			games := week_schedule.Games[team]
+			sort.Slice(games, func(i, j int) bool {
+				return games[i].Day < games[j].Day
+			})
+		}
+	}
+
+	return schedule, nil
+}
+
+// Function to get the fantasy weeks, either the ones listed in the config or the league weeks with the merged ones joined
+func (c LeagueConfig) FantasyWeeks(raw_weeks []RawWeek) ([]week, error) {
+
+	var weeks []week
+	if len(c.Weeks) > 0 {
+		for _, boundary := range c.Weeks {
+			start, err := time.Parse(DateLayout, boundary.StartDate)
+			if err != nil {
+				return nil, fmt.Errorf("parsing week start %q: %w", boundary.StartDate, err)
+			}
+			end, err := time.Parse(DateLayout, boundary.EndDate)
+			if err != nil {
+				return nil, fmt.Errorf("parsing week end %q: %w", boundary.EndDate, err)
+			}
+			weeks = append(weeks, week{start: start, end: end})
+		}
+	} else {
+		merged, err := c.fromLeagueWeeks(raw_weeks)
+		if err != nil {
+			return nil, err
+		}
+		weeks = merged
+	}
+
+	// Nothing before the season starts counts, so weeks that end before it are left out
+	if c.SeasonStart != "" {
+		season_start, err := time.Parse(DateLayout, c.SeasonStart)
+		if err != nil {
+			return nil, fmt.Errorf("parsing season start %q: %w", c.SeasonStart, err)
+		}
+		for len(weeks) > 0 && weeks[0].end.Before(season_start) {
+			weeks = weeks[1:]
+		}
+		if len(weeks) > 0 && weeks[0].start.Before(season_start) {
+			weeks[0].start = season_start
+		}
+	}
+
+	if len(weeks) == 0 {
+		return nil, fmt.Errorf("no weeks to build")
+	}
+	for i, w := range weeks {
+		if w.end.Before(w.start) {
+			return nil, fmt.Errorf("week %d ends before it starts", i+1)
+		}
+		if i > 0 && !w.start.After(weeks[i-1].end) {
+			return nil, fmt.Errorf("week %d starts before week %d ends", i+1, i)
+		}
+	}
+
+	return weeks, nil
+}
+
+// Function to turn the league weeks into fantasy weeks, each merged group becomes one week
+func (c LeagueConfig) fromLeagueWeeks(raw_weeks []RawWeek) ([]week, error) {
+
+	sorted := append([]RawWeek{}, raw_weeks...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].WeekNumber < sorted[j].WeekNumber
+	})
+
+	// Every week after the first one of a merged group joins the week before it
+	joins_previous := make(map[int]bool)
+	for _, group := range c.MergedWeeks {
+		for i := 1; i < len(group); i++ {
+			if group[i] != group[i-1]+1 {
+				return nil, fmt.Errorf("merged weeks %v must be consecutive", group)
+			}
+			joins_previous[group[i]] = true
+		}
+	}
+
+	var weeks []week
+	for _, raw_week := range sorted {
+		if c.LastWeek > 0 && raw_week.WeekNumber > c.LastWeek {
+			break
+		}
+		start, err := parseLeagueDate(raw_week.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseLeagueDate(raw_week.EndDate)
+		if err != nil {
+			return nil, err
+		}
+
+		if joins_previous[raw_week.WeekNumber] && len(weeks) > 0 {
+			weeks[len(weeks)-1].end = end
+			continue
+		}
+		weeks = append(weeks, week{start: start, end: end})
+	}
+
+	return weeks, nil
+}
+
+// Function to write the week schedules as a versioned schedule file
+func WriteSchedule(path string, season string, schedule map[string]h.WeekSchedule) error {
+	data, err := json.MarshalIndent(h.NewScheduleFile(season, schedule), "", "    ")
+	if err != nil {
+		return fmt.Errorf("encoding schedule: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing schedule: %w", err)
+	}
+	return nil
+}
+
+// Function to read a schedule file in any layout so it can be written back out in the versioned format
+func UpgradeSchedule(path string) (map[string]h.WeekSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading schedule: %w", err)
+	}
+	weeks, err := h.ParseSeasonSchedule(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing schedule: %w", err)
+	}
+
+	// The Python scripts wrote games without a team yet under "null"
+	for _, week := range weeks {
+		delete(week.TeamSchedules, "null")
+	}
+	return weeks, nil
+}
+
+// Function to get the calendar date of a league week timestamp
+func parseLeagueDate(value string) (time.Time, error) {
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing league week date %q: %w", value, err)
+	}
+	date = date.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// Function to find the week [date] falls in, or -1 if it is outside every week
+func findWeek(weeks []week, date time.Time) int {
+	for i, w := range weeks {
+		if !date.Before(w.start) && !date.After(w.end) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Function to count the days from [start] to [end]
+func daysBetween(start time.Time, end time.Time) int {
+	return int(end.Sub(start).Hours() / 24)
+}
@@ -0,0 +1,43 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Fantasy league settings that decide how the raw schedule is split into weeks
+type LeagueConfig struct {
+	// Games before this date are skipped and the first week starts no earlier than it
+	SeasonStart string `json:"season_start"`
+
+	// Explicit fantasy weeks, when empty the weeks come from the raw schedule
+	Weeks []WeekBoundary `json:"weeks"`
+
+	// Groups of league week numbers that make up one fantasy week, like the All-Star break
+	MergedWeeks [][]int `json:"merged_weeks"`
+
+	// Last league week to include, 0 includes every week
+	LastWeek int `json:"last_week"`
+}
+
+// First and last day of a fantasy week, both in MM/DD/YYYY
+type WeekBoundary struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// Function to load a league config from disk
+func LoadLeagueConfig(path string) (LeagueConfig, error) {
+	var config LeagueConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("reading league config: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("parsing league config: %w", err)
+	}
+
+	return config, nil
+}
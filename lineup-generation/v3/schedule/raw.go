@@ -0,0 +1,58 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Raw league schedule in the format of the NBA's scheduleLeagueV2.json
+type RawSchedule struct {
+	LeagueSchedule struct {
+		SeasonYear string        `json:"seasonYear"`
+		GameDates  []RawGameDate `json:"gameDates"`
+		Weeks      []RawWeek     `json:"weeks"`
+	} `json:"leagueSchedule"`
+}
+
+// Every game played on one date
+type RawGameDate struct {
+	GameDate string    `json:"gameDate"`
+	Games    []RawGame `json:"games"`
+}
+
+type RawGame struct {
+	GameId          string  `json:"gameId"`
+	GameDateTimeUTC string  `json:"gameDateTimeUTC"`
+	HomeTeam RawTeam `json:"homeTeam"`
+	AwayTeam RawTeam `json:"awayTeam"`
+}
+
+type RawTeam struct {
+	TeamTricode string `json:"teamTricode"`
+}
+
+// League week, the dates are UTC timestamps
+type RawWeek struct {
+	WeekNumber int    `json:"weekNumber"`
+	StartDate  string `json:"startDate"`
+	EndDate    string `json:"endDate"`
+}
+
+// Game ids of regular season games start with this prefix, preseason, All-Star and playoff games don't count
+const RegularSeasonPrefix = "002"
+
+// Function to load a raw league schedule from disk
+func LoadRawSchedule(path string) (RawSchedule, error) {
+	var raw RawSchedule
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return raw, fmt.Errorf("reading raw schedule: %w", err)
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return raw, fmt.Errorf("parsing raw schedule: %w", err)
+	}
+
+	return raw, nil
+}
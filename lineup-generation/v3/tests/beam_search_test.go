@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"testing"
+
+	h "v3/helpers"
+)
+
+// Schedule where the free agents play more games than the streamers on the roster
+func createStreamingSchedule() *h.WeekSchedule {
+	schedule := createMockSchedule()
+	schedule.TeamSchedules["OKC"] = []int{0, 2, 4, 5}
+	schedule.TeamSchedules["LAL"] = []int{0, 1, 3, 5}
+	schedule.TeamSchedules["HOU"] = []int{2, 3, 4}
+	schedule.TeamSchedules["NYK"] = []int{0, 5}
+	return schedule
+}
+
+func TestBeamSearchImprovesLineup(t *testing.T) {
+	schedule := createStreamingSchedule()
+	freeAgents := createMockFreeAgents()
+
+	setup_state := h.InitSetupState(schedule, createMockRoster(), freeAgents, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	root := h.InitState(schedule, setup_state, freeAgents, h.DefaultAcquisitionLimits(schedule.GetGameSpan()))
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+
+	if best.GetScore() <= root.GetScore() {
+		t.Errorf("Expected beam search to improve on %d, got %d", root.GetScore(), best.GetScore())
+	}
+	if root.GetDay() != 0 {
+		t.Errorf("Root state should not be modified by the search, day is %d", root.GetDay())
+	}
+
+	// Every addition has a matching removal and no more acquisitions are used than allowed
+	rosters := best.ToRosters(setup_state)
+	if len(rosters) != schedule.GetGameSpan() {
+		t.Fatalf("Expected %d rosters, got %d", schedule.GetGameSpan(), len(rosters))
+	}
+	acquisitions := 0
+	for _, roster := range rosters {
+		if len(roster.Additions) != len(roster.Removals) {
+			t.Errorf("Day %d has %d additions and %d removals", roster.Day, len(roster.Additions), len(roster.Removals))
+		}
+		acquisitions += len(roster.Additions)
+	}
+	if acquisitions != root.GetAcqLeft()-best.GetAcqLeft() {
+		t.Errorf("Expected %d acquisitions, got %d", root.GetAcqLeft()-best.GetAcqLeft(), acquisitions)
+	}
+	if best.GetAcqLeft() < 0 {
+		t.Errorf("Acquisitions left should never be negative, got %d", best.GetAcqLeft())
+	}
+}
+
+func TestBeamSearchKeepsNonStreamablePlayers(t *testing.T) {
+	schedule := createStreamingSchedule()
+	freeAgents := createMockFreeAgents()
+
+	setup_state := h.InitSetupState(schedule, createMockRoster(), freeAgents, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	root := h.InitState(schedule, setup_state, freeAgents, h.DefaultAcquisitionLimits(schedule.GetGameSpan()))
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+
+	// Players above the threshold are never dropped and stay in their optimal slots
+	for _, roster := range best.ToRosters(setup_state) {
+		for position, player := range setup_state.GetOptimalSlotting()[roster.Day] {
+			if roster.Roster[position].Name != player.Name {
+				t.Errorf("Day %d: expected %s at %s, got %s", roster.Day, player.Name, position, roster.Roster[position].Name)
+			}
+		}
+		for _, removed := range roster.Removals {
+			if removed.AvgPoints > 30.0 {
+				t.Errorf("Day %d: non-streamable player %s was dropped", roster.Day, removed.Name)
+			}
+		}
+	}
+}
+
+func TestBeamSearchWithoutAcquisitions(t *testing.T) {
+	schedule := createStreamingSchedule()
+
+	// With no free agents there is nothing to do, so the best state is the root state
+	setup_state := h.InitSetupState(schedule, createMockRoster(), []h.Player{}, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	root := h.InitState(schedule, setup_state, []h.Player{}, h.DefaultAcquisitionLimits(schedule.GetGameSpan()))
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+
+	if best.GetScore() != root.GetScore() {
+		t.Errorf("Expected score %d, got %d", root.GetScore(), best.GetScore())
+	}
+	if best.GetAcqLeft() != root.GetAcqLeft() {
+		t.Errorf("Expected %d acquisitions left, got %d", root.GetAcqLeft(), best.GetAcqLeft())
+	}
+}
+
+func TestBeamSearchAcquisitionLimits(t *testing.T) {
+	schedule := createStreamingSchedule()
+	freeAgents := createMockFreeAgents()
+	setup_state := h.InitSetupState(schedule, createMockRoster(), freeAgents, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+
+	search := func(limits h.AcquisitionLimits) (*h.State, *h.State) {
+		root := h.InitState(schedule, setup_state, freeAgents, limits)
+		return root, h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+	}
+
+	// The season budget caps the weekly limit
+	root, best := search(h.AcquisitionLimits{PerWeek: 6, PerDay: h.NoLimit, Season: 1, WaiverDays: h.DefaultWaiverDays})
+	if root.GetAcqLeft() != 1 || best.GetAcqLeft() != 0 {
+		t.Errorf("Expected one acquisition to be available and used, got %d and %d left", root.GetAcqLeft(), best.GetAcqLeft())
+	}
+	if best.GetScore() <= root.GetScore() {
+		t.Errorf("Expected the single acquisition to improve on %d, got %d", root.GetScore(), best.GetScore())
+	}
+
+	// No daily pickups leaves the roster as it is
+	root, best = search(h.AcquisitionLimits{PerWeek: 4, PerDay: 0, Season: h.NoLimit, WaiverDays: h.DefaultWaiverDays})
+	if best.GetScore() != root.GetScore() || best.GetAcqLeft() != 4 {
+		t.Errorf("Expected no moves, got score %d with %d acquisitions left", best.GetScore(), best.GetAcqLeft())
+	}
+
+	// Every plan found follows the daily cap
+	_, best = search(h.AcquisitionLimits{PerWeek: 4, PerDay: 1, Season: h.NoLimit, WaiverDays: h.DefaultWaiverDays})
+	if !best.IsWithinLimits() {
+		t.Errorf("Expected the best state to follow the limits")
+	}
+	for _, roster := range best.ToRosters(setup_state) {
+		if len(roster.Additions) > 1 {
+			t.Errorf("Day %d has %d additions with a daily cap of 1", roster.Day, len(roster.Additions))
+		}
+	}
+}
+
+func TestBeamSearchRespectsWaivers(t *testing.T) {
+	schedule := createStreamingSchedule()
+	setup_state := h.InitSetupState(schedule, createMockRoster(), createMockFreeAgents(), 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+
+	// Free agents stay on waivers until day 3, so nobody can be picked up before then
+	free_agents := createMockFreeAgents()
+	for i := range free_agents {
+		free_agents[i].WaiverUntilDay = 3
+	}
+	root := h.InitState(schedule, setup_state, free_agents, h.DefaultAcquisitionLimits(schedule.GetGameSpan()))
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+	for _, roster := range best.ToRosters(setup_state) {
+		if roster.Day < 3 && len(roster.Additions) > 0 {
+			t.Errorf("Day %d has additions while every free agent is on waivers", roster.Day)
+		}
+	}
+
+	// Dropped players stay unavailable for the league's waiver period
+	if best.GetLimits().WaiverDays != h.DefaultWaiverDays {
+		t.Errorf("Expected %d waiver days, got %d", h.DefaultWaiverDays, best.GetLimits().WaiverDays)
+	}
+	for _, dropped := range best.GetDroppedPlayers() {
+		if dropped.Countdown <= 0 || dropped.Countdown > h.DefaultWaiverDays {
+			t.Errorf("Unexpected waiver countdown %d for %s", dropped.Countdown, dropped.Player.Name)
+		}
+	}
+
+	// Nobody clears waivers during the week, so the roster stays as it is
+	for i := range free_agents {
+		free_agents[i].WaiverUntilDay = schedule.GetGameSpan()
+	}
+	root = h.InitState(schedule, setup_state, free_agents, h.DefaultAcquisitionLimits(schedule.GetGameSpan()))
+	if best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth); best.GetScore() != root.GetScore() {
+		t.Errorf("Expected no moves with every free agent on waivers, got %d vs %d", best.GetScore(), root.GetScore())
+	}
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	h "v3/helpers"
+)
+
+func TestScheduleDates(t *testing.T) {
+	first := h.WeekSchedule{StartDate: "10/21/2025", EndDate: "10/26/2025", GameSpan: 6, TeamSchedules: map[string][]int{"OKC": {0}}}
+	second := h.WeekSchedule{StartDate: "10/27/2025", EndDate: "11/02/2025", GameSpan: 7, TeamSchedules: map[string][]int{"OKC": {1}}}
+	stitched := h.StitchWeekSchedules([]int{1, 2}, []h.WeekSchedule{first, second})
+
+	if date := stitched.GetISODate(0); date != "2025-10-21" {
+		t.Errorf("Expected day 0 on 2025-10-21, got %s", date)
+	}
+	if date := stitched.GetISODate(11); date != "2025-11-01" {
+		t.Errorf("Expected day 11 on 2025-11-01, got %s", date)
+	}
+
+	// Dates map back to days, the time of day doesn't matter
+	if day, ok := stitched.GetDay(time.Date(2025, time.October, 28, 19, 30, 0, 0, time.UTC)); !ok || day != 7 {
+		t.Errorf("Expected 2025-10-28 on day 7, got %d %v", day, ok)
+	}
+	for _, date := range []time.Time{time.Date(2025, time.October, 20, 0, 0, 0, 0, time.UTC), time.Date(2025, time.November, 3, 0, 0, 0, 0, time.UTC)} {
+		if _, ok := stitched.GetDay(date); ok {
+			t.Errorf("Expected %s to be outside the schedule", date.Format(h.ISODateLayout))
+		}
+	}
+
+	// League weeks are found by number
+	if start, end, ok := stitched.GetWeekDays(2); !ok || start != 6 || end != 13 {
+		t.Errorf("Expected week 2 on days 6 to 13, got %d to %d %v", start, end, ok)
+	}
+	if _, _, ok := stitched.GetWeekDays(3); ok {
+		t.Error("Expected week 3 to be missing")
+	}
+
+	// The season lookup finds the week that contains a date
+	season := map[string]h.WeekSchedule{"1": first, "2": second}
+	date, _ := h.ParseISODate("2025-11-02")
+	if week, day, err := h.FindWeekForDate(season, date); err != nil || week != 2 || day != 6 {
+		t.Errorf("Expected 2025-11-02 on day 6 of week 2, got day %d of week %d %v", day, week, err)
+	}
+	date, _ = h.ParseISODate("2025-12-25")
+	if _, _, err := h.FindWeekForDate(season, date); err == nil {
+		t.Error("Expected an error for a date outside the season")
+	}
+}
+
+func TestSplitByWeekDates(t *testing.T) {
+	first := h.WeekSchedule{StartDate: "10/21/2025", EndDate: "10/23/2025", GameSpan: 3, TeamSchedules: map[string][]int{}}
+	second := h.WeekSchedule{StartDate: "10/24/2025", EndDate: "10/26/2025", GameSpan: 3, TeamSchedules: map[string][]int{}}
+	stitched := h.StitchWeekSchedules([]int{1, 2}, []h.WeekSchedule{first, second})
+
+	rosters := make([]h.Roster, 6)
+	for day := range rosters {
+		rosters[day].Day = day
+	}
+	plan := h.SplitByWeek(&stitched, []int{1, 2}, rosters)
+
+	// The day restarts each week, the date keeps counting
+	roster := plan[1].Lineup[1]
+	if roster.Day != 1 || roster.Date != "2025-10-25" {
+		t.Errorf("Expected day 1 of week 2 on 2025-10-25, got day %d on %s", roster.Day, roster.Date)
+	}
+}
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	h "v3/helpers"
+)
+
+// Two three day weeks, the long term free agent plays once in the first week and every day of the second
+func createHorizonSchedule() *h.WeekSchedule {
+	return &h.WeekSchedule{
+		GameSpan:   6,
+		Weeks:      []int{1, 2},
+		WeekStarts: []int{0, 3},
+		TeamSchedules: map[string][]int{
+			"SSS": {0},
+			"AAA": {1, 2},
+			"BBB": {2, 3, 4, 5},
+		},
+	}
+}
+
+func createHorizonPlayers() ([]h.Player, []h.Player) {
+	guard := []string{"PG", "G"}
+	roster := []h.Player{{Name: "Streamer", AvgPoints: 5.0, Team: "SSS", ValidPositions: guard}}
+	free_agents := []h.Player{
+		{Name: "Short Term", AvgPoints: 9.0, Team: "AAA", ValidPositions: guard},
+		{Name: "Long Term", AvgPoints: 7.0, Team: "BBB", ValidPositions: guard},
+	}
+	return roster, free_agents
+}
+
+// Function to run the beam search over the horizon schedule with the given limits and lock
+func planHorizon(limits h.AcquisitionLimits, lock h.LockMode) (*h.WeekSchedule, *h.State, *h.State, []h.WeekPlan) {
+	schedule := createHorizonSchedule()
+	roster, free_agents := createHorizonPlayers()
+	setup_state := h.InitSetupState(schedule, roster, free_agents, 100.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, lock)
+	root := h.InitState(schedule, setup_state, free_agents, limits)
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+	return schedule, root, best, h.SplitByWeek(schedule, []int{1, 2}, best.ToRosters(setup_state))
+}
+
+func TestStitchWeekSchedules(t *testing.T) {
+	first := h.WeekSchedule{StartDate: "10/21/2025", EndDate: "10/26/2025", GameSpan: 6, TeamSchedules: map[string][]int{"OKC": {0, 2, 4}}}
+	second := h.WeekSchedule{StartDate: "10/27/2025", EndDate: "11/02/2025", GameSpan: 7, TeamSchedules: map[string][]int{"OKC": {1, 6}, "DET": {0}}}
+
+	stitched := h.StitchWeekSchedules([]int{1, 2}, []h.WeekSchedule{first, second})
+	if stitched.GetGameSpan() != 13 || stitched.StartDate != "10/21/2025" || stitched.EndDate != "11/02/2025" {
+		t.Errorf("Unexpected span %d from %s to %s", stitched.GetGameSpan(), stitched.StartDate, stitched.EndDate)
+	}
+	if !compareIntSlices(stitched.GetTeamSchedule("OKC"), []int{0, 2, 4, 7, 12}) || !compareIntSlices(stitched.GetTeamSchedule("DET"), []int{6}) {
+		t.Errorf("Expected the second week's days to follow the first's, got %v", stitched.TeamSchedules)
+	}
+
+	// Days map back to the week they fall in
+	if index := stitched.GetWeekIndex(5); index != 0 {
+		t.Errorf("Expected day 5 in the first week, got %d", index)
+	}
+	if start, end := stitched.GetWeekBounds(8); start != 6 || end != 13 {
+		t.Errorf("Expected the second week to cover days 6-12, got %d-%d", start, end-1)
+	}
+	if games := stitched.GetGamesInWeek("OKC", 0); games != 3 {
+		t.Errorf("Expected 3 OKC games in the first week, got %d", games)
+	}
+	if points := stitched.WeeklyPoints(h.Player{Team: "OKC", AvgPoints: 10.0}, 6); points != 20.0 {
+		t.Errorf("Expected 20 points in the second week, got %v", points)
+	}
+
+	// A single week is its own horizon
+	if starts := first.GetWeekStarts(); !compareIntSlices(starts, []int{0}) {
+		t.Errorf("Expected a single week to start on day 0, got %v", starts)
+	}
+}
+
+func TestLoadHorizonSchedule(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_horizon_*.json")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(`{
+		"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {"OKC": [0, 2, 4]}},
+		"2": {"startDate": "10/27/2025", "endDate": "11/02/2025", "gameSpan": 7, "games": {"OKC": [1]}},
+		"3": {"startDate": "11/03/2025", "endDate": "11/09/2025", "gameSpan": 7, "games": {"OKC": [3]}}
+	}`); err != nil {
+		t.Fatalf("Failed to write test data: %v", err)
+	}
+	tmpFile.Close()
+
+	schedule, err := h.LoadHorizonSchedule(tmpFile.Name(), []int{1, 2})
+	if err != nil {
+		t.Fatalf("Failed to load horizon: %v", err)
+	}
+	if schedule.GetGameSpan() != 13 || !compareIntSlices(schedule.GetWeekStarts(), []int{0, 6}) || !compareIntSlices(schedule.GetTeamSchedule("OKC"), []int{0, 2, 4, 7}) {
+		t.Errorf("Unexpected horizon schedule %+v", schedule)
+	}
+
+	if _, err := h.LoadHorizonSchedule(tmpFile.Name(), []int{1, 3}); err == nil {
+		t.Error("Expected an error for weeks that aren't consecutive")
+	}
+	if _, err := h.LoadHorizonSchedule(tmpFile.Name(), []int{3, 4}); err == nil {
+		t.Error("Expected an error for a week missing from the schedule")
+	}
+}
+
+func TestBeamSearchAcrossWeeks(t *testing.T) {
+	// One acquisition a week: the short term pickup covers the first week and the long term one the second
+	limits := h.AcquisitionLimits{PerWeek: 1, PerDay: h.NoLimit, Season: h.NoLimit, WaiverDays: h.DefaultWaiverDays}
+	_, root, best, plan := planHorizon(limits, h.LockDaily)
+	if root.GetScore() != 5 || best.GetScore() != 44 {
+		t.Errorf("Expected the score to go from 5 to 44, got %d to %d", root.GetScore(), best.GetScore())
+	}
+	if !best.IsWithinLimits() {
+		t.Error("Expected the plan to follow the weekly limits")
+	}
+
+	// The plan is split by week and each week's days count from its own start
+	if len(plan) != 2 || plan[0].Week != 1 || plan[1].Week != 2 {
+		t.Fatalf("Expected a plan for weeks 1 and 2, got %+v", plan)
+	}
+	for _, week := range plan {
+		additions := 0
+		for day, roster := range week.Lineup {
+			if roster.Week != week.Week || roster.Day != day {
+				t.Errorf("Expected week %d day %d, got week %d day %d", week.Week, day, roster.Week, roster.Day)
+			}
+			additions += len(roster.Additions)
+		}
+		if additions != 1 {
+			t.Errorf("Expected one acquisition in week %d, got %d", week.Week, additions)
+		}
+	}
+
+	// With one acquisition for the whole season, next week's games make the long term pickup worth more
+	limits.Season = 1
+	_, _, best, plan = planHorizon(limits, h.LockDaily)
+	if best.GetScore() != 33 {
+		t.Errorf("Expected a score of 33 with one acquisition left, got %d", best.GetScore())
+	}
+	for _, roster := range plan[0].Lineup {
+		for _, player := range roster.Additions {
+			if player.Name != "Long Term" {
+				t.Errorf("Expected the long term pickup, got %s", player.Name)
+			}
+		}
+	}
+}
+
+func TestBeamSearchAcrossWeeksWeeklyLock(t *testing.T) {
+	limits := h.AcquisitionLimits{PerWeek: 1, PerDay: h.NoLimit, Season: h.NoLimit, WaiverDays: h.DefaultWaiverDays}
+	_, _, best, plan := planHorizon(limits, h.LockWeekly)
+
+	// Each week locks on its first day, so the short term pickup plays the first week and the long term one the second
+	if best.GetScore() != 39 {
+		t.Errorf("Expected a score of 39 with weekly locks, got %d", best.GetScore())
+	}
+	for _, week := range plan {
+		for _, roster := range week.Lineup {
+			if len(roster.Additions) > 0 && roster.Day != 0 {
+				t.Errorf("Week %d day %d has additions after the week locked", week.Week, roster.Day)
+			}
+		}
+	}
+}
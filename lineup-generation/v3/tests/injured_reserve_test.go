@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"testing"
+
+	h "v3/helpers"
+)
+
+// Four day week where the injured star is back on day 2, the rostered streamer only plays on day 0 and two free agents
+// play every day
+func createIRWeek() (*h.WeekSchedule, []h.Player, []h.Player) {
+	schedule := &h.WeekSchedule{
+		StartDate: "10/21/2025",
+		GameSpan:  4,
+		TeamSchedules: map[string][]int{
+			"AAA": {0, 1, 2, 3},
+			"SSS": {0},
+			"FFF": {0, 1, 2, 3},
+			"GGG": {0, 1, 2, 3},
+		},
+	}
+	return_day := 2
+	guard := []string{"PG", "G"}
+	roster := []h.Player{
+		{Name: "Injured Star", AvgPoints: 40.0, Team: "AAA", ValidPositions: []string{"C"}, InjuryStatus: h.InjuryOut, ReturnDay: &return_day},
+		{Name: "Streamer", AvgPoints: 5.0, Team: "SSS", ValidPositions: guard},
+	}
+	free_agents := []h.Player{
+		{Name: "Free Agent 1", AvgPoints: 10.0, Team: "FFF", ValidPositions: guard},
+		{Name: "Free Agent 2", AvgPoints: 8.0, Team: "GGG", ValidPositions: guard},
+	}
+	return schedule, roster, free_agents
+}
+
+func TestPlanInjuredReserve(t *testing.T) {
+	schedule, roster, _ := createIRWeek()
+	roster = append(roster,
+		h.Player{Name: "Out For Season", AvgPoints: 35.0, Team: "AAA", ValidPositions: []string{"SF"}, InjuryStatus: h.InjuryReserve},
+		h.Player{Name: "Questionable", AvgPoints: 35.0, Team: "AAA", ValidPositions: []string{"PF"}, InjuryStatus: h.InjuryQuestionable},
+	)
+
+	// One IR slot goes to the player who is out the longest, a questionable player isn't eligible
+	setup_state := h.InitSetupState(schedule, roster, []h.Player{}, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	moves := setup_state.GetIRMoves()
+	if len(moves) != 1 || moves[0].Player.Name != "Out For Season" || !moves[0].MoveToIR || moves[0].ReturnDay != 4 {
+		t.Errorf("Expected only Out For Season to move into IR for the week, got %v", moves)
+	}
+
+	// A player already in IR keeps the slot and is activated when he's back
+	roster[2].OnIR = true
+	roster[2].ReturnDay = new(int)
+	*roster[2].ReturnDay = 3
+	setup_state = h.InitSetupState(schedule, roster, []h.Player{}, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	moves = setup_state.GetIRMoves()
+	if len(moves) != 1 || moves[0].Player.Name != "Out For Season" || moves[0].MoveToIR || moves[0].ReturnDay != 3 {
+		t.Errorf("Expected Out For Season to stay in IR until day 3, got %v", moves)
+	}
+}
+
+func TestBeamSearchStreamsIntoIRSpot(t *testing.T) {
+	schedule, roster, free_agents := createIRWeek()
+	setup_state := h.InitSetupState(schedule, roster, free_agents, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	per_week := 2
+	request := h.Request{MaxAcquisitionsPerWeek: &per_week}
+
+	root := h.InitState(schedule, setup_state, free_agents, request.GetAcquisitionLimits(schedule.GetGameSpan()))
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+	rosters := best.ToRosters(setup_state)
+
+	// The star moves into IR on day 0 and is activated on his return day
+	if len(rosters[0].Actions) != 1 || rosters[0].Actions[0].Action != h.ActionMoveToIR || rosters[0].Actions[0].Player.Name != "Injured Star" {
+		t.Errorf("Expected the star to move into IR on day 0, got %v", rosters[0].Actions)
+	}
+	if len(rosters[2].Actions) != 1 || rosters[2].Actions[0].Action != h.ActionActivate || rosters[2].Actions[0].Player.Name != "Injured Star" {
+		t.Errorf("Expected the star to be activated on day 2, got %v", rosters[2].Actions)
+	}
+	for _, roster := range rosters[2:] {
+		if roster.Roster["C"].Name != "Injured Star" {
+			t.Errorf("Day %d: expected the star back at C, got %v", roster.Day, roster.Roster["C"])
+		}
+	}
+
+	// The freed spot takes a streamer without a drop, and the roster is back to its size once the star returns
+	if len(rosters[0].Additions) <= len(rosters[0].Removals) {
+		t.Errorf("Expected a pickup into the open spot on day 0, got %v for %v", rosters[0].Additions, rosters[0].Removals)
+	}
+	additions, removals := 0, 0
+	for _, roster := range rosters {
+		additions += len(roster.Additions)
+		removals += len(roster.Removals)
+		for _, player := range roster.Removals {
+			if player.IsOpenRosterSpot() {
+				t.Errorf("Day %d: the open spot shows up as a drop", roster.Day)
+			}
+		}
+	}
+	if additions != removals {
+		t.Errorf("Expected as many drops as pickups after the star is back, got %d and %d", removals, additions)
+	}
+	if best.GetScore() <= root.GetScore() {
+		t.Errorf("Expected the open spot to add points, got %d vs %d", best.GetScore(), root.GetScore())
+	}
+}
+
+func TestBeamSearchSlotsReturningIRPlayer(t *testing.T) {
+
+	// A player in IR below the threshold still takes back a roster spot, so he has to be slotted once he's activated
+	schedule, roster, free_agents := createIRWeek()
+	roster[0].AvgPoints = 20.0
+	roster[0].OnIR = true
+	setup_state := h.InitSetupState(schedule, roster, free_agents, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	per_week := 2
+	request := h.Request{MaxAcquisitionsPerWeek: &per_week}
+
+	root := h.InitState(schedule, setup_state, free_agents, request.GetAcquisitionLimits(schedule.GetGameSpan()))
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+	rosters := best.ToRosters(setup_state)
+	if len(rosters[2].Actions) != 1 || rosters[2].Actions[0].Action != h.ActionActivate {
+		t.Fatalf("Expected the player to be activated on day 2, got %v", rosters[2].Actions)
+	}
+	for _, roster := range rosters {
+		if started := roster.Roster["C"].Name == "Injured Star"; started != (roster.Day >= 2) {
+			t.Errorf("Day %d: expected him at C only from day 2, got %v", roster.Day, roster.Roster["C"])
+		}
+	}
+}
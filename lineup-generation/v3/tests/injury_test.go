@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"testing"
+
+	h "v3/helpers"
+)
+
+func TestPlayProbability(t *testing.T) {
+	return_day := 2
+	questionable := h.Player{Name: "Questionable", AvgPoints: 20.0, InjuryStatus: h.InjuryQuestionable, ReturnDay: &return_day}
+	if questionable.PlayProbability(0) != 0.5 || questionable.PlayProbability(2) != 1.0 {
+		t.Errorf("Expected 0.5 before the return day and 1 from then on, got %v and %v", questionable.PlayProbability(0), questionable.PlayProbability(2))
+	}
+
+	// A player only marked as injured is out for the whole plan
+	injured := h.Player{Name: "Injured", Injured: true}
+	if injured.GetInjuryStatus() != h.InjuryOut || !injured.IsOutBetween(0, 7) {
+		t.Errorf("Expected an injured player without a status to be out, got %q", injured.GetInjuryStatus())
+	}
+	if questionable.IsOutBetween(0, 7) {
+		t.Error("Expected a questionable player to have a chance of playing")
+	}
+
+	if err := h.ValidateInjuries([]h.Player{questionable, injured, {Name: "Hurt", InjuryStatus: "SORE"}}); err == nil {
+		t.Error("Expected an error for an unknown injury status")
+	}
+	negative := -1
+	if err := h.ValidateInjuries([]h.Player{{Name: "Early", InjuryStatus: h.InjuryOut, ReturnDay: &negative}}); err == nil {
+		t.Error("Expected an error for a negative return day")
+	}
+}
+
+func TestSlottingBringsPlayersBack(t *testing.T) {
+	schedule := &h.WeekSchedule{
+		StartDate:     "10/21/2025",
+		GameSpan:      4,
+		TeamSchedules: map[string][]int{"AAA": {0, 1, 2, 3}},
+	}
+	return_day := 2
+	roster := []h.Player{
+		{Name: "Returning Center", AvgPoints: 40.0, Team: "AAA", ValidPositions: []string{"C"}, InjuryStatus: h.InjuryOut, ReturnDay: &return_day},
+		{Name: "Doubtful Guard", AvgPoints: 40.0, Team: "AAA", ValidPositions: []string{"PG", "G"}, InjuryStatus: h.InjuryDoubtful},
+		{Name: "Season Ending", AvgPoints: 40.0, Team: "AAA", ValidPositions: []string{"SF", "F"}, InjuryStatus: h.InjuryReserve},
+	}
+	setup_state := h.InitSetupState(schedule, roster, []h.Player{}, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+
+	for day, lineup := range setup_state.GetOptimalSlotting() {
+		if returned := lineup["C"].Name == "Returning Center"; returned != (day >= return_day) {
+			t.Errorf("Day %d: expected the center back from day %d, got %v at C", day, return_day, lineup["C"])
+		}
+		if lineup["PG"].Name != "Doubtful Guard" {
+			t.Errorf("Day %d: expected the doubtful guard to keep his slot, got %v", day, lineup["PG"])
+		}
+		for pos, player := range lineup {
+			if player.Name == "Season Ending" {
+				t.Errorf("Day %d: expected the player on IR to be left out, found at %s", day, pos)
+			}
+		}
+	}
+
+	// Each game is weighted by the chance of playing it
+	if points := schedule.WeeklyPoints(roster[1], 0); points != 40.0 {
+		t.Errorf("Expected the doubtful guard to be worth 40 over four games, got %v", points)
+	}
+	if points := schedule.WeeklyPoints(roster[0], 0); points != 80.0 {
+		t.Errorf("Expected the returning center to be worth 80 over his two games, got %v", points)
+	}
+}
+
+func TestBeamSearchWeighsInjuredFreeAgents(t *testing.T) {
+	schedule := &h.WeekSchedule{
+		StartDate:     "10/21/2025",
+		GameSpan:      2,
+		TeamSchedules: map[string][]int{"AAA": {0, 1}, "BBB": {0, 1}},
+	}
+	guard := []string{"PG", "G"}
+	free_agents := []h.Player{
+		{Name: "Questionable Star", AvgPoints: 20.0, Team: "AAA", ValidPositions: guard, InjuryStatus: h.InjuryQuestionable},
+		{Name: "Healthy Role Player", AvgPoints: 12.0, Team: "BBB", ValidPositions: guard},
+	}
+	roster := []h.Player{{Name: "Streamer", AvgPoints: 1.0, Team: "CCC", ValidPositions: guard}}
+	setup_state := h.InitSetupState(schedule, roster, free_agents, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	per_week := 1
+	request := h.Request{MaxAcquisitionsPerWeek: &per_week}
+
+	// Half of 20 a game is worth less than a sure 12
+	root := h.InitState(schedule, setup_state, free_agents, request.GetAcquisitionLimits(schedule.GetGameSpan()))
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+	if best.GetScore() != 24 {
+		t.Errorf("Expected 24 from the healthy player, got %d", best.GetScore())
+	}
+}
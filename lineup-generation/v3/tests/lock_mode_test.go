@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"testing"
+
+	h "v3/helpers"
+)
+
+// Centers that compete for C and the UT slots, one of them plays a single big game while the rest play every day
+func createLockSchedule() (*h.WeekSchedule, []h.Player) {
+	schedule := &h.WeekSchedule{GameSpan: 3, TeamSchedules: map[string][]int{"AAA": {0, 1, 2}, "BBB": {1}}}
+	roster := []h.Player{
+		{Name: "Center 1", AvgPoints: 20.0, Team: "AAA", ValidPositions: []string{"C"}},
+		{Name: "Center 2", AvgPoints: 21.0, Team: "AAA", ValidPositions: []string{"C"}},
+		{Name: "Center 3", AvgPoints: 22.0, Team: "AAA", ValidPositions: []string{"C"}},
+		{Name: "Center 4", AvgPoints: 23.0, Team: "AAA", ValidPositions: []string{"C"}},
+		{Name: "Center 5", AvgPoints: 40.0, Team: "BBB", ValidPositions: []string{"C"}},
+	}
+	return schedule, roster
+}
+
+func TestOptimizeSlottingWeeklyLock(t *testing.T) {
+	schedule, roster := createLockSchedule()
+
+	// With daily locks the big scorer starts on the one day he plays
+	daily := h.InitSetupState(schedule, roster, []h.Player{}, 0.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	template := daily.GetTemplate()
+	for pos, player := range daily.GetOptimalSlotting()[1] {
+		if player.Name == "Center 5" && template.IsBench(pos) {
+			t.Errorf("Expected Center 5 to start on day 1 with a daily lock, found at %s", pos)
+		}
+	}
+
+	// With a weekly lock one game isn't worth a slot that the others fill every day, and the lineup never changes
+	weekly := h.InitSetupState(schedule, roster, []h.Player{}, 0.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockWeekly)
+	if weekly.GetLockMode() != h.LockWeekly {
+		t.Errorf("Expected lock mode %q, got %q", h.LockWeekly, weekly.GetLockMode())
+	}
+	first := weekly.GetOptimalSlotting()[0]
+	for day := 0; day < schedule.GetGameSpan(); day++ {
+		lineup := weekly.GetOptimalSlotting()[day]
+		if len(lineup) != len(first) {
+			t.Fatalf("Day %d: expected %d slotted players, got %d", day, len(first), len(lineup))
+		}
+		for pos, player := range lineup {
+			if first[pos].Name != player.Name {
+				t.Errorf("Day %d: expected %s at %s, got %s", day, first[pos].Name, pos, player.Name)
+			}
+			if player.Name == "Center 5" && !template.IsBench(pos) {
+				t.Errorf("Day %d: expected Center 5 on the bench, found at %s", day, pos)
+			}
+		}
+	}
+
+	// Locked slots aren't open to streamers on the days their players sit
+	for day, unused := range weekly.GetUnusedPositions() {
+		if unused["C"] || unused["UT1"] || unused["UT2"] || unused["UT3"] {
+			t.Errorf("Day %d: expected the locked center slots to be used, got %v", day, unused)
+		}
+	}
+}
+
+func TestBeamSearchWeeklyLock(t *testing.T) {
+	schedule := createStreamingSchedule()
+	free_agents := createMockFreeAgents()
+
+	setup_state := h.InitSetupState(schedule, createMockRoster(), free_agents, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockWeekly)
+	root := h.InitState(schedule, setup_state, free_agents, h.DefaultAcquisitionLimits(schedule.GetGameSpan()))
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+	if best.GetScore() <= root.GetScore() {
+		t.Errorf("Expected beam search to improve on %d, got %d", root.GetScore(), best.GetScore())
+	}
+
+	// Every pickup happens before the lock and every player keeps his slot for the whole week
+	rosters := best.ToRosters(setup_state)
+	for _, roster := range rosters {
+		if roster.Day > 0 && len(roster.Additions) > 0 {
+			t.Errorf("Day %d has additions after the weekly lock", roster.Day)
+		}
+		for pos, player := range roster.Roster {
+			if rosters[0].Roster[pos].Name != player.Name {
+				t.Errorf("Day %d: expected %s at %s, got %s", roster.Day, rosters[0].Roster[pos].Name, pos, player.Name)
+			}
+		}
+	}
+
+	// Locked streamers only score on the days they play
+	total := 0.0
+	for day, lineup := range best.GetLineups() {
+		for _, player := range lineup.GetRoster() {
+			if player.Name != "" && schedule.IsPlaying(day, player.Team) {
+				total += player.AvgPoints
+			}
+		}
+	}
+	if int(total) != best.GetScore() {
+		t.Errorf("Expected a score of %d from the locked lineups, got %d", int(total), best.GetScore())
+	}
+}
+
+func TestLockModeValidate(t *testing.T) {
+	for _, mode := range []h.LockMode{h.LockDaily, h.LockWeekly} {
+		if err := mode.Validate(); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", mode, err)
+		}
+	}
+	if h.LockMode("hourly").Validate() == nil {
+		t.Error("Expected an error for an unknown lock mode")
+	}
+
+	// Locking at tip-off isn't planned yet, so it's refused rather than planned like a daily lock
+	if h.LockPerGame.Validate() == nil {
+		t.Error("Expected an error for a per game lock")
+	}
+
+	request := h.Request{}
+	if request.GetLockMode() != h.LockDaily {
+		t.Errorf("Expected lock mode to default to %q, got %q", h.LockDaily, request.GetLockMode())
+	}
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	h "v3/helpers"
+)
+
+// Full day of players where the guards and forwards compete for the flexible slots
+func createSlottingPlayers() []h.Player {
+	return []h.Player{
+		{Name: "Guard 1", AvgPoints: 48.0, ValidPositions: []string{"PG", "SG", "G"}},
+		{Name: "Guard 2", AvgPoints: 41.0, ValidPositions: []string{"PG", "G"}},
+		{Name: "Guard 3", AvgPoints: 36.0, ValidPositions: []string{"SG", "G"}},
+		{Name: "Guard 4", AvgPoints: 22.0, ValidPositions: []string{"PG", "SG", "G"}},
+		{Name: "Wing 1", AvgPoints: 39.0, ValidPositions: []string{"SG", "SF", "G", "F"}},
+		{Name: "Wing 2", AvgPoints: 27.0, ValidPositions: []string{"SF", "F"}},
+		{Name: "Forward 1", AvgPoints: 45.0, ValidPositions: []string{"SF", "PF", "F"}},
+		{Name: "Forward 2", AvgPoints: 30.0, ValidPositions: []string{"PF", "C", "F"}},
+		{Name: "Center 1", AvgPoints: 52.0, ValidPositions: []string{"C"}},
+		{Name: "Center 2", AvgPoints: 25.0, ValidPositions: []string{"PF", "C"}},
+		{Name: "Center 3", AvgPoints: 18.0, ValidPositions: []string{"C"}},
+	}
+}
+
+// Function to slot players with the original backtracking search
+func fitPlayers(ssm *h.SetupStateMetadata, players []h.Player) *h.FitPlayersContext {
+	sorted := append([]h.Player{}, players...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i].ValidPositions) < len(sorted[j].ValidPositions)
+	})
+	template := ssm.GetTemplate()
+	ctx := &h.FitPlayersContext{BestLineup: make(map[string]h.Player), MaxScore: ssm.CalculateMaxScore(sorted)}
+	ssm.FitPlayers(sorted, make(map[string]h.Player), template.SlotOrder(), ctx, 0)
+	return ctx
+}
+
+func TestMaxWeightAssignment(t *testing.T) {
+	forbidden := math.Inf(-1)
+	weights := [][]float64{
+		{4, 1, forbidden},
+		{3, forbidden, forbidden},
+		{forbidden, forbidden, forbidden},
+	}
+
+	// Row 0 gives up its best column so row 1 can be placed, row 2 has nowhere to go
+	assignment := h.MaxWeightAssignment(weights)
+	expected := []int{1, 0, -1}
+	for i := range expected {
+		if assignment[i] != expected[i] {
+			t.Fatalf("Expected assignment %v, got %v", expected, assignment)
+		}
+	}
+
+	if len(h.MaxWeightAssignment([][]float64{})) != 0 {
+		t.Error("Expected an empty assignment for no rows")
+	}
+}
+
+func TestMatchPlayersMatchesBacktracking(t *testing.T) {
+	setup_state := h.InitSetupState(createMockSchedule(), []h.Player{}, []h.Player{}, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	players := createSlottingPlayers()
+
+	// Every prefix of the day's players should reach the same restrictiveness score as the recursion
+	template := setup_state.GetTemplate()
+	for n := 1; n <= len(players); n++ {
+		matched := setup_state.MatchPlayers(players[:n])
+		ctx := fitPlayers(setup_state, players[:n])
+		if len(matched) != n {
+			t.Errorf("%d players: expected every player slotted, got %d", n, len(matched))
+		}
+		if score := setup_state.ScoreRoster(matched); score != ctx.TopScore {
+			t.Errorf("%d players: expected score %d, got %d", n, ctx.TopScore, score)
+		}
+		for pos, player := range matched {
+			if !template.Accepts(pos, player) {
+				t.Errorf("%d players: %s can't play %s", n, player.Name, pos)
+			}
+		}
+	}
+}
+
+func TestMatchPlayersBenchesLowestScorers(t *testing.T) {
+	setup_state := h.InitSetupState(createMockSchedule(), []h.Player{}, []h.Player{}, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	players := createCrowdedSlottingPlayers()
+
+	// Centers compete for C and the UT slots, the weakest ones go to the bench
+	lineup := setup_state.MatchPlayers(players)
+	template := setup_state.GetTemplate()
+	for pos, player := range lineup {
+		if template.IsBench(pos) && player.Name != "Center 5" && player.Name != "Center 3" && player.Name != "Guard 4" {
+			t.Errorf("Expected only low scorers on the bench, found %s at %s", player.Name, pos)
+		}
+	}
+	for pos, player := range lineup {
+		if (player.Name == "Center 4" || player.Name == "Center 1") && template.IsBench(pos) {
+			t.Errorf("Expected %s in a starting slot", player.Name)
+		}
+	}
+	if len(lineup) != len(template.SlotOrder()) {
+		t.Errorf("Expected every slot filled, got %d", len(lineup))
+	}
+}
+
+// Full day with more players than starting slots, where the recursion can't exit early
+func createCrowdedSlottingPlayers() []h.Player {
+	return append(createSlottingPlayers(),
+		h.Player{Name: "Center 4", AvgPoints: 60.0, ValidPositions: []string{"C"}},
+		h.Player{Name: "Center 5", AvgPoints: 5.0, ValidPositions: []string{"C"}},
+	)
+}
+
+func BenchmarkMatchPlayers(b *testing.B) {
+	setup_state := h.InitSetupState(createMockSchedule(), []h.Player{}, []h.Player{}, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	players := createSlottingPlayers()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		setup_state.MatchPlayers(players)
+	}
+}
+
+func BenchmarkFitPlayers(b *testing.B) {
+	setup_state := h.InitSetupState(createMockSchedule(), []h.Player{}, []h.Player{}, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	players := createSlottingPlayers()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fitPlayers(setup_state, players)
+	}
+}
+
+func BenchmarkMatchPlayersCrowded(b *testing.B) {
+	setup_state := h.InitSetupState(createMockSchedule(), []h.Player{}, []h.Player{}, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	players := createCrowdedSlottingPlayers()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		setup_state.MatchPlayers(players)
+	}
+}
+
+func BenchmarkFitPlayersCrowded(b *testing.B) {
+	setup_state := h.InitSetupState(createMockSchedule(), []h.Player{}, []h.Player{}, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	players := createCrowdedSlottingPlayers()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fitPlayers(setup_state, players)
+	}
+}
+
+func TestOptimizeSlottingByPoints(t *testing.T) {
+	schedule := &h.WeekSchedule{GameSpan: 1, TeamSchedules: map[string][]int{"AAA": {0}}}
+
+	// Every player plays on an overflow day with one more player than there are slots
+	roster := createCrowdedSlottingPlayers()
+	roster = append(roster, h.Player{Name: "Guard 5", AvgPoints: 12.0, ValidPositions: []string{"PG", "G"}})
+	for i := range roster {
+		roster[i].Team = "AAA"
+	}
+
+	setup_state := h.InitSetupState(schedule, roster, []h.Player{}, 0.0, h.DefaultRosterTemplate(), h.SlotByPoints, h.LockDaily)
+	if setup_state.GetSlottingMode() != h.SlotByPoints {
+		t.Errorf("Expected slotting mode %q, got %q", h.SlotByPoints, setup_state.GetSlottingMode())
+	}
+
+	// The ten best players start, the next three sit and the lowest scorer is left out
+	lineup := setup_state.GetOptimalSlotting()[0]
+	template := setup_state.GetTemplate()
+	benched := map[string]bool{"Guard 4": true, "Center 3": true, "Guard 5": true}
+	started := 0.0
+	for pos, player := range lineup {
+		if template.IsBench(pos) != benched[player.Name] {
+			t.Errorf("Unexpected %s at %s", player.Name, pos)
+		}
+		if player.Name == "Center 5" {
+			t.Errorf("Expected the lowest scorer to be left out, found at %s", pos)
+		}
+		if !template.IsBench(pos) {
+			started += player.AvgPoints
+		}
+	}
+	if started != 403.0 {
+		t.Errorf("Expected 403 points started, got %v", started)
+	}
+}
+
+func TestSlottingModeValidate(t *testing.T) {
+	if h.SlotByPoints.Validate() != nil || h.SlotByRestrictiveness.Validate() != nil {
+		t.Error("Expected the known slotting modes to be valid")
+	}
+	if h.SlottingMode("fastest").Validate() == nil {
+		t.Error("Expected an error for an unknown slotting mode")
+	}
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"testing"
+
+	h "v3/helpers"
+)
+
+// Four day week where one free agent plays a back-to-back on the last two days and is projected to rest the second
+// night, while the other plays the same two days at a lower average
+func createProjectionWeek() (*h.WeekSchedule, []h.Player, []h.Player) {
+	schedule := &h.WeekSchedule{
+		StartDate: "10/21/2025",
+		GameSpan:  4,
+		TeamSchedules: map[string][]int{
+			"SSS": {0},
+			"AAA": {2, 3},
+			"BBB": {2, 3},
+		},
+	}
+	guard := []string{"PG", "G"}
+	roster := []h.Player{{Name: "Streamer", AvgPoints: 5.0, Team: "SSS", ValidPositions: guard}}
+	free_agents := []h.Player{
+		{Name: "Back To Back", AvgPoints: 10.0, Team: "AAA", ValidPositions: guard, Projections: map[string]float64{"2025-10-23": 12.0, "3": 0.0}},
+		{Name: "Steady", AvgPoints: 7.0, Team: "BBB", ValidPositions: guard},
+	}
+	return schedule, roster, free_agents
+}
+
+func TestPlayerProjectedPoints(t *testing.T) {
+	schedule, _, free_agents := createProjectionWeek()
+	player := free_agents[0]
+
+	// The day comes first, then the date, then the average
+	if points := schedule.GetPoints(player, 2); points != 12.0 {
+		t.Errorf("Expected 12 points from the date's projection, got %v", points)
+	}
+	if points := schedule.GetPoints(player, 3); points != 0.0 || !schedule.IsResting(player, 3) {
+		t.Errorf("Expected the second night of the back-to-back to be a rest day, got %v", points)
+	}
+	if points := schedule.GetPoints(player, 1); points != 10.0 || schedule.IsResting(player, 1) {
+		t.Errorf("Expected the average of 10 without a projection, got %v", points)
+	}
+	if points := schedule.WeeklyPoints(player, 0); points != 12.0 {
+		t.Errorf("Expected 12 points over the week, got %v", points)
+	}
+}
+
+func TestBeamSearchUsesProjections(t *testing.T) {
+	schedule, roster, free_agents := createProjectionWeek()
+	setup_state := h.InitSetupState(schedule, roster, free_agents, 100.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	per_week := 1
+	request := h.Request{MaxAcquisitionsPerWeek: &per_week}
+	limits := request.GetAcquisitionLimits(schedule.GetGameSpan())
+
+	// On averages the back-to-back is worth 20, but he only plays one of the two nights so the steady player's 14 wins
+	root := h.InitState(schedule, setup_state, free_agents, limits)
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+	if improvement := best.GetScore() - root.GetScore(); improvement != 14 {
+		t.Errorf("Expected an improvement of 14, got %d", improvement)
+	}
+	for _, roster := range best.ToRosters(setup_state) {
+		for _, player := range roster.Additions {
+			if player.Name != "Steady" {
+				t.Errorf("Expected Steady to be picked up, got %s on day %d", player.Name, roster.Day)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"testing"
+
+	h "v3/helpers"
+)
+
+// Four day week where the rostered streamer plays on the first and last day, one free agent only plays early in the
+// week and the other only late
+func createReplanWeek() (*h.WeekSchedule, []h.Player, []h.Player) {
+	schedule := &h.WeekSchedule{
+		StartDate: "10/21/2025",
+		GameSpan:  4,
+		TeamSchedules: map[string][]int{
+			"SSS": {0, 3},
+			"AAA": {0, 1},
+			"BBB": {2, 3},
+		},
+	}
+	guard := []string{"PG", "G"}
+	roster := []h.Player{{Name: "Streamer", AvgPoints: 5.0, Team: "SSS", ValidPositions: guard}}
+	free_agents := []h.Player{
+		{Name: "Early", AvgPoints: 9.0, Team: "AAA", ValidPositions: guard},
+		{Name: "Late", AvgPoints: 7.0, Team: "BBB", ValidPositions: guard},
+	}
+	return schedule, roster, free_agents
+}
+
+func TestReplanFromCurrentDay(t *testing.T) {
+	schedule, roster, free_agents := createReplanWeek()
+	setup_state := h.InitSetupState(schedule, roster, free_agents, 100.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	limits := h.DefaultAcquisitionLimits(schedule.GetGameSpan())
+
+	// Only the streamer's game on the last day is left to score
+	root := h.InitStateOnDay(schedule, setup_state, free_agents, limits, 2)
+	if root.GetDay() != 2 || root.GetScore() != 5 {
+		t.Fatalf("Expected the root to start on day 2 with 5 points, got day %d with %d", root.GetDay(), root.GetScore())
+	}
+
+	// The early free agent has no games left, so the late one is picked up on the current day
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+	if improvement := best.GetScore() - root.GetScore(); improvement != 9 {
+		t.Errorf("Expected an improvement of 9 over the remaining days, got %d", improvement)
+	}
+	rosters := best.ToRosters(setup_state)
+	if len(rosters) != 2 || rosters[0].Day != 2 {
+		t.Fatalf("Expected rosters for days 2 and 3 only, got %d starting on day %d", len(rosters), rosters[0].Day)
+	}
+	if len(rosters[0].Additions) != 1 || rosters[0].Additions[0].Name != "Late" {
+		t.Errorf("Expected Late to be picked up on day 2, got %v", rosters[0].Additions)
+	}
+}
+
+func TestReplanWithAcquisitionsUsed(t *testing.T) {
+	schedule, roster, free_agents := createReplanWeek()
+	setup_state := h.InitSetupState(schedule, roster, free_agents, 100.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+
+	// Every acquisition of the week was made before re-planning
+	used := 2
+	request := h.Request{MaxAcquisitionsPerWeek: &used, AcquisitionsUsed: 2}
+	limits := request.GetAcquisitionLimits(schedule.GetGameSpan())
+	if limits.WeeklyLimit() != 0 {
+		t.Fatalf("Expected no acquisitions left, got %d", limits.WeeklyLimit())
+	}
+
+	root := h.InitStateOnDay(schedule, setup_state, free_agents, limits, 2)
+	best := h.BeamSearch(schedule, setup_state, root, h.BeamWidth)
+	if best.GetScore() != root.GetScore() {
+		t.Errorf("Expected no moves with the week's budget used, got %d vs %d", best.GetScore(), root.GetScore())
+	}
+
+	// A later week of the plan starts with a fresh budget
+	if next := limits.ForWeek(0); next.WeeklyLimit() != 2 {
+		t.Errorf("Expected 2 acquisitions in the next week, got %d", next.WeeklyLimit())
+	}
+}
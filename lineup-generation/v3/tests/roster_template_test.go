@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"testing"
+
+	h "v3/helpers"
+)
+
+// Template for a league with fewer starting slots, 2 UT spots that take anyone and 4 bench spots
+func createCustomTemplate() h.RosterTemplate {
+	any_position := []string{"PG", "SG", "SF", "PF", "C"}
+	return h.RosterTemplate{
+		Slots: []h.Slot{
+			{Name: "PG", Positions: []string{"PG"}, Weight: 5},
+			{Name: "C", Positions: []string{"C"}, Weight: 4},
+			{Name: "UT1", Positions: any_position, Weight: 2},
+			{Name: "UT2", Positions: any_position, Weight: 2},
+		},
+		Bench: 4,
+		IR:    1,
+	}
+}
+
+func TestRosterTemplateSlots(t *testing.T) {
+	template := createCustomTemplate()
+	if err := template.Validate(); err != nil {
+		t.Fatalf("Expected template to be valid, got %v", err)
+	}
+
+	expected := []string{"PG", "C", "UT1", "UT2", "BE1", "BE2", "BE3", "BE4"}
+	order := template.SlotOrder()
+	if len(order) != len(expected) {
+		t.Fatalf("Expected slot order %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("Expected slot order %v, got %v", expected, order)
+			break
+		}
+	}
+
+	forward := h.Player{Name: "Forward", ValidPositions: []string{"SF", "PF", "F"}}
+	if template.Accepts("PG", forward) || !template.Accepts("UT1", forward) || !template.Accepts("BE4", forward) {
+		t.Errorf("Forward should only fit the UT and bench slots")
+	}
+	if template.Weight("BE2") != h.BenchWeight || template.Weight("UT2") != 2 {
+		t.Errorf("Unexpected slot weights %d and %d", template.Weight("BE2"), template.Weight("UT2"))
+	}
+}
+
+func TestRosterTemplateValidate(t *testing.T) {
+	template := createCustomTemplate()
+	template.Slots = append(template.Slots, h.Slot{Name: "BE1", Positions: []string{"PG"}, Weight: 1})
+	if template.Validate() == nil {
+		t.Error("Expected an error for a slot that clashes with a bench slot")
+	}
+
+	template = createCustomTemplate()
+	template.Slots[0].Positions = nil
+	if template.Validate() == nil {
+		t.Error("Expected an error for a slot with no positions")
+	}
+
+	template = h.RosterTemplate{Bench: 3}
+	if template.Validate() == nil {
+		t.Error("Expected an error for a template with no starting slots")
+	}
+}
+
+func TestSetupStateWithCustomTemplate(t *testing.T) {
+	schedule := &h.WeekSchedule{
+		StartDate:     "10/21/2025",
+		EndDate:       "10/22/2025",
+		GameSpan:      2,
+		TeamSchedules: map[string][]int{"AAA": {0}, "BBB": {0, 1}},
+	}
+	roster := []h.Player{
+		{Name: "Point Guard 1", AvgPoints: 50.0, Team: "AAA", ValidPositions: []string{"PG", "G"}},
+		{Name: "Point Guard 2", AvgPoints: 45.0, Team: "AAA", ValidPositions: []string{"PG", "G"}},
+		{Name: "Center", AvgPoints: 40.0, Team: "BBB", ValidPositions: []string{"C"}},
+		{Name: "Small Forward", AvgPoints: 35.0, Team: "AAA", ValidPositions: []string{"SF", "F"}},
+		{Name: "Power Forward", AvgPoints: 33.0, Team: "AAA", ValidPositions: []string{"PF", "F"}},
+	}
+
+	setup_state := h.InitSetupState(schedule, roster, []h.Player{}, 30.0, createCustomTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+
+	// On day 0 the four starting slots are full and the extra player sits on the bench
+	day0 := setup_state.GetOptimalSlotting()[0]
+	for _, slot := range []string{"PG", "C", "UT1", "UT2"} {
+		if day0[slot].Name == "" {
+			t.Errorf("Expected %s to be filled on day 0, got %v", slot, day0)
+		}
+	}
+	if len(day0) != 5 {
+		t.Errorf("Expected 5 players slotted on day 0, got %d", len(day0))
+	}
+	if len(setup_state.GetUnusedPositions()[0]) != 0 {
+		t.Errorf("Expected no unused positions on day 0, got %v", setup_state.GetUnusedPositions()[0])
+	}
+
+	// On day 1 only the center plays, leaving the other starting slots open for streamers
+	unused := setup_state.GetUnusedPositions()[1]
+	if len(unused) != 3 || !unused["PG"] || !unused["UT1"] || !unused["UT2"] {
+		t.Errorf("Expected PG, UT1 and UT2 to be unused on day 1, got %v", unused)
+	}
+}
+
+func TestRosterTemplateDerivedWeights(t *testing.T) {
+	template := h.DefaultRosterTemplate()
+	expected := map[string]int{"PG": 4, "SF": 4, "C": 4, "G": 3, "F": 3, "UT1": 2, "UT3": 2, "BE1": 1, "BE3": 1}
+	weights := template.Weights()
+	for slot, weight := range expected {
+		if weights[slot] != weight {
+			t.Errorf("Expected %s to weigh %d, got %d", slot, weight, weights[slot])
+		}
+	}
+
+	// Explicit weights override the derived ones
+	template.Slots[0].Weight = 10
+	if template.Weight("PG") != 10 || template.Weight("SG") != 4 {
+		t.Errorf("Expected PG to weigh 10 and SG 4, got %d and %d", template.Weight("PG"), template.Weight("SG"))
+	}
+}
+
+func TestCustomTemplateEarlyExit(t *testing.T) {
+	template := createCustomTemplate()
+	for i := range template.Slots {
+		template.Slots[i].Weight = 0
+	}
+
+	schedule := &h.WeekSchedule{GameSpan: 1, TeamSchedules: map[string][]int{}}
+	setup_state := h.InitSetupState(schedule, []h.Player{}, []h.Player{}, 30.0, template, h.SlotByRestrictiveness, h.LockDaily)
+
+	players := []h.Player{
+		{Name: "Center", ValidPositions: []string{"C"}},
+		{Name: "Point Guard", ValidPositions: []string{"PG", "G"}},
+		{Name: "Small Forward", ValidPositions: []string{"SF", "F"}},
+	}
+
+	// PG and C are the narrowest slots, the forward can only go in a UT slot
+	max_score := setup_state.CalculateMaxScore(players)
+	if max_score != 8 {
+		t.Fatalf("Expected a max score of 8, got %d", max_score)
+	}
+
+	ctx := &h.FitPlayersContext{BestLineup: make(map[string]h.Player), MaxScore: max_score}
+	setup_state.FitPlayers(players, make(map[string]h.Player), template.SlotOrder(), ctx, 0)
+	if !ctx.EarlyExit || ctx.TopScore != max_score {
+		t.Errorf("Expected early exit at score %d, got exit %v at score %d", max_score, ctx.EarlyExit, ctx.TopScore)
+	}
+	if ctx.BestLineup["PG"].Name != "Point Guard" || ctx.BestLineup["C"].Name != "Center" || ctx.BestLineup["UT1"].Name != "Small Forward" {
+		t.Errorf("Unexpected lineup %v", ctx.BestLineup)
+	}
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	h "v3/helpers"
+	"v3/schedule"
+)
+
+const rawScheduleFixture = "./resources/schedule_raw_fixture.json"
+
+// League config that starts the season on a Tuesday and merges league weeks 3 and 4
+func createLeagueConfig() schedule.LeagueConfig {
+	return schedule.LeagueConfig{SeasonStart: "10/21/2025", MergedWeeks: [][]int{{3, 4}}, LastWeek: 4}
+}
+
+// Function to check a built week against its expected dates and games
+func checkBuiltWeek(t *testing.T, weeks map[string]h.WeekSchedule, key string, start string, end string, span int, games map[string][]int) {
+	t.Helper()
+
+	week, ok := weeks[key]
+	if !ok {
+		t.Fatalf("Expected week %s to be built", key)
+	}
+	if week.StartDate != start || week.EndDate != end || week.GameSpan != span {
+		t.Errorf("Week %s: expected %s-%s over %d days, got %s-%s over %d days", key, start, end, span, week.StartDate, week.EndDate, week.GameSpan)
+	}
+	if len(week.TeamSchedules) != len(games) {
+		t.Errorf("Week %s: expected %d teams, got %v", key, len(games), week.TeamSchedules)
+	}
+	for team, days := range games {
+		if !compareIntSlices(week.GetTeamSchedule(team), days) {
+			t.Errorf("Week %s: expected %s to play on %v, got %v", key, team, days, week.GetTeamSchedule(team))
+		}
+	}
+}
+
+func TestBuildScheduleFromLeagueWeeks(t *testing.T) {
+	raw, err := schedule.LoadRawSchedule(rawScheduleFixture)
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	weeks, err := schedule.Build(raw, createLeagueConfig())
+	if err != nil {
+		t.Fatalf("Failed to build schedule: %v", err)
+	}
+
+	// Preseason, All-Star and games after the last week are left out, the merged weeks share one day index
+	if len(weeks) != 3 {
+		t.Fatalf("Expected 3 weeks, got %d", len(weeks))
+	}
+	checkBuiltWeek(t, weeks, "1", "10/21/2025", "10/26/2025", 6, map[string][]int{"OKC": {0, 2}, "HOU": {0}, "LAL": {0, 2}, "GSW": {0}})
+	checkBuiltWeek(t, weeks, "2", "10/27/2025", "11/02/2025", 7, map[string][]int{"HOU": {1}, "GSW": {1}})
+	checkBuiltWeek(t, weeks, "3", "11/03/2025", "11/16/2025", 14, map[string][]int{"OKC": {1}, "GSW": {1}, "LAL": {11}, "HOU": {11}})
+
+	// Each team's side of a game knows the opponent, whether it is at home and when it tips off
+	week := weeks["1"]
+	at_home, away := true, false
+	expected := []h.Game{{Day: 0, Opponent: "HOU", Home: &at_home, TipOff: "2025-10-21T23:30:00Z"}, {Day: 2, Opponent: "LAL", Home: &away}}
+	if games := week.GetGames("OKC"); !reflect.DeepEqual(games, expected) {
+		t.Errorf("Expected OKC games %+v, got %+v", expected, games)
+	}
+}
+
+func TestBuildScheduleFromConfigWeeks(t *testing.T) {
+	raw, err := schedule.LoadRawSchedule(rawScheduleFixture)
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	// Explicit weeks take the place of the league weeks
+	config := schedule.LeagueConfig{Weeks: []schedule.WeekBoundary{
+		{StartDate: "10/21/2025", EndDate: "10/23/2025"},
+		{StartDate: "10/24/2025", EndDate: "11/04/2025"},
+	}}
+	weeks, err := schedule.Build(raw, config)
+	if err != nil {
+		t.Fatalf("Failed to build schedule: %v", err)
+	}
+	checkBuiltWeek(t, weeks, "1", "10/21/2025", "10/23/2025", 3, map[string][]int{"OKC": {0, 2}, "HOU": {0}, "LAL": {0, 2}, "GSW": {0}})
+	checkBuiltWeek(t, weeks, "2", "10/24/2025", "11/04/2025", 12, map[string][]int{"HOU": {4}, "GSW": {4, 11}, "OKC": {11}})
+
+	// Weeks that overlap or merges that skip a week can't be built
+	config.Weeks[1].StartDate = "10/23/2025"
+	if _, err := schedule.Build(raw, config); err == nil {
+		t.Error("Expected an error for overlapping weeks")
+	}
+	if _, err := schedule.Build(raw, schedule.LeagueConfig{MergedWeeks: [][]int{{2, 4}}}); err == nil {
+		t.Error("Expected an error for merged weeks that aren't consecutive")
+	}
+}
+
+func TestBuildScheduleRoundTrip(t *testing.T) {
+	raw, err := schedule.LoadRawSchedule(rawScheduleFixture)
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	weeks, err := schedule.Build(raw, createLeagueConfig())
+	if err != nil {
+		t.Fatalf("Failed to build schedule: %v", err)
+	}
+
+	// The written file loads with the same loader the server uses
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	if err := schedule.WriteSchedule(path, "2025-26", weeks); err != nil {
+		t.Fatalf("Failed to write schedule: %v", err)
+	}
+	loaded, err := h.LoadWeekSchedule(path, 3)
+	if err != nil {
+		t.Fatalf("Failed to load written schedule: %v", err)
+	}
+	checkBuiltWeek(t, map[string]h.WeekSchedule{"3": loaded}, "3", "11/03/2025", "11/16/2025", 14, map[string][]int{"OKC": {1}, "GSW": {1}, "LAL": {11}, "HOU": {11}})
+
+	if _, err := schedule.LoadRawSchedule(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing raw schedule")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the schedule to be written: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	h "v3/helpers"
+	"v3/schedule"
+)
+
+// The same week in the versioned format and both legacy layouts
+const versionedScheduleJSON = `{
+	"version": 1,
+	"season": "2025-26",
+	"weeks": {"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {
+		"OKC": [{"day": 2, "opponent": "LAL", "home": false}, {"day": 0, "opponent": "HOU", "home": true, "tipoff": "2025-10-21T23:30:00Z"}],
+		"HOU": [{"day": 0, "opponent": "OKC", "home": false, "tipoff": "2025-10-21T23:30:00Z"}]
+	}}}
+}`
+
+const legacyV2ScheduleJSON = `{"schedule": {"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {
+	"OKC": {"0": true, "2": true},
+	"HOU": {"0": true}
+}}}}`
+
+const legacyV3ScheduleJSON = `{"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {
+	"OKC": [0, 2],
+	"HOU": [0],
+	"null": [1]
+}}}`
+
+func TestParseSeasonScheduleLayouts(t *testing.T) {
+	for name, data := range map[string]string{"versioned": versionedScheduleJSON, "legacy v2": legacyV2ScheduleJSON, "legacy v3": legacyV3ScheduleJSON} {
+		weeks, err := h.ParseSeasonSchedule([]byte(data))
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", name, err)
+		}
+		week := weeks["1"]
+		if week.StartDate != "10/21/2025" || week.GameSpan != 6 {
+			t.Errorf("%s: unexpected week %+v", name, week)
+		}
+		if !compareIntSlices(week.GetTeamSchedule("OKC"), []int{0, 2}) || !compareIntSlices(week.GetTeamSchedule("HOU"), []int{0}) {
+			t.Errorf("%s: unexpected team schedules %v", name, week.TeamSchedules)
+		}
+	}
+
+	// Only the versioned format has the game details, which come back in day order
+	weeks, _ := h.ParseSeasonSchedule([]byte(versionedScheduleJSON))
+	week := weeks["1"]
+	if games := week.GetGames("OKC"); len(games) != 2 || games[0].Opponent != "HOU" || games[0].Home == nil || !*games[0].Home || games[0].TipOff != "2025-10-21T23:30:00Z" {
+		t.Errorf("Unexpected OKC games %+v", games)
+	}
+
+	if _, err := h.ParseSeasonSchedule([]byte(`{"version": 99, "weeks": {}}`)); err == nil {
+		t.Error("Expected an error for an unknown schedule version")
+	}
+}
+
+func TestUpgradeLegacySchedule(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "legacy.json")
+	if err := os.WriteFile(legacy, []byte(legacyV3ScheduleJSON), 0644); err != nil {
+		t.Fatalf("Failed to write legacy schedule: %v", err)
+	}
+
+	// Games without a team are dropped and every day becomes a game in the versioned file
+	weeks, err := schedule.UpgradeSchedule(legacy)
+	if err != nil {
+		t.Fatalf("Failed to upgrade: %v", err)
+	}
+	upgraded := filepath.Join(dir, "upgraded.json")
+	if err := schedule.WriteSchedule(upgraded, "2025-26", weeks); err != nil {
+		t.Fatalf("Failed to write upgraded schedule: %v", err)
+	}
+
+	week, err := h.LoadWeekSchedule(upgraded, 1)
+	if err != nil {
+		t.Fatalf("Failed to load upgraded schedule: %v", err)
+	}
+	if _, ok := week.TeamSchedules["null"]; ok {
+		t.Error("Expected games without a team to be dropped")
+	}
+	if games := week.GetGames("OKC"); len(games) != 2 || games[0].Day != 0 || games[1].Day != 2 || games[0].Opponent != "" || games[0].Home != nil {
+		t.Errorf("Expected a game without details for each OKC day, got %+v", games)
+	}
+
+	// Legacy files don't know which side is at home, so the upgraded file doesn't claim one
+	data, err := os.ReadFile(upgraded)
+	if err != nil {
+		t.Fatalf("Failed to read upgraded schedule: %v", err)
+	}
+	if strings.Contains(string(data), `"home"`) {
+		t.Error("Expected the upgraded schedule to leave out home")
+	}
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	h "v3/helpers"
+)
+
+// Function to write a one week schedule for a season where OKC plays on the given days
+func writeSeasonSchedule(t *testing.T, path string, season string, days string, mod_time time.Time) {
+	data := `{"version": 1, "season": "` + season + `", "weeks": {"1": {"startDate": "10/21/2025", "endDate": "10/23/2025", "gameSpan": 3, "games": {
+		"OKC": [` + days + `]
+	}}}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write schedule: %v", err)
+	}
+	if err := os.Chtimes(path, mod_time, mod_time); err != nil {
+		t.Fatalf("Failed to set the modification time: %v", err)
+	}
+}
+
+func TestScheduleRegistry(t *testing.T) {
+	dir := t.TempDir()
+	loaded := time.Now().Add(-time.Hour)
+	writeSeasonSchedule(t, filepath.Join(dir, "schedule2024-2025.json"), "2024-25", `{"day": 0}`, loaded)
+	writeSeasonSchedule(t, filepath.Join(dir, "schedule2025-2026.json"), "2025-26", `{"day": 1}`, loaded)
+	if err := os.WriteFile(filepath.Join(dir, "league2025-2026.json"), []byte(`{"weeks": []}`), 0644); err != nil {
+		t.Fatalf("Failed to write league config: %v", err)
+	}
+
+	registry, err := h.NewScheduleRegistry(dir)
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	if seasons := registry.Seasons(); len(seasons) != 2 || registry.DefaultSeason() != "2025-26" {
+		t.Fatalf("Expected two seasons with 2025-26 as the default, got %v", seasons)
+	}
+
+	// An empty season falls back to the latest one
+	schedule, err := registry.LoadHorizon("", []int{1}, h.LoadOptions{})
+	if err != nil || !compareIntSlices(schedule.GetTeamSchedule("OKC"), []int{1}) {
+		t.Errorf("Expected OKC on day 1 of the default season, got %v %v", schedule.TeamSchedules, err)
+	}
+	if _, err := registry.LoadHorizon("1999-00", []int{1}, h.LoadOptions{}); err == nil {
+		t.Error("Expected an error for an unknown season")
+	}
+
+	// A changed file is picked up on reload
+	writeSeasonSchedule(t, filepath.Join(dir, "schedule2025-2026.json"), "2025-26", `{"day": 2}`, loaded.Add(time.Minute))
+	registry.Reload()
+	schedule, _ = registry.LoadHorizon("2025-26", []int{1}, h.LoadOptions{})
+	if !compareIntSlices(schedule.GetTeamSchedule("OKC"), []int{2}) {
+		t.Errorf("Expected the reloaded schedule with OKC on day 2, got %v", schedule.TeamSchedules)
+	}
+
+	// A broken file keeps the previous schedule
+	if err := os.WriteFile(filepath.Join(dir, "schedule2025-2026.json"), []byte(`{"version": 1,`), 0644); err != nil {
+		t.Fatalf("Failed to write schedule: %v", err)
+	}
+	os.Chtimes(filepath.Join(dir, "schedule2025-2026.json"), loaded.Add(2*time.Minute), loaded.Add(2*time.Minute))
+	registry.Reload()
+	schedule, err = registry.LoadHorizon("2025-26", []int{1}, h.LoadOptions{})
+	if err != nil || !compareIntSlices(schedule.GetTeamSchedule("OKC"), []int{2}) {
+		t.Errorf("Expected the previous schedule to be kept, got %v %v", schedule.TeamSchedules, err)
+	}
+
+	// A removed file takes its season with it
+	os.Remove(filepath.Join(dir, "schedule2024-2025.json"))
+	registry.Reload()
+	if registry.HasSeason("2024-25") {
+		t.Error("Expected 2024-25 to be gone after its file was removed")
+	}
+}
+
+func TestScheduleRegistryRejectInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeSeasonSchedule(t, filepath.Join(dir, "schedule2025-2026.json"), "2025-26", `{"day": 1}, {"day": 1}`, time.Now())
+
+	registry, err := h.NewScheduleRegistry(dir)
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	if errs, _ := registry.GetErrors("2025-26"); len(errs) != 1 || errs[0].Kind != h.DuplicateDay {
+		t.Errorf("Expected the duplicate day to be found when loading, got %v", errs)
+	}
+	if _, err := registry.LoadHorizon("2025-26", []int{1}, h.LoadOptions{RejectInvalid: true}); err == nil {
+		t.Error("Expected the invalid week to be refused")
+	}
+
+	if _, err := h.NewScheduleRegistry(t.TempDir()); err == nil {
+		t.Error("Expected an error for a directory without schedules")
+	}
+}
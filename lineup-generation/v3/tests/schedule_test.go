@@ -180,7 +180,7 @@ func TestInitWeekScheduleWithInvalidJSON(t *testing.T) {
 // TestInitWeekScheduleWithRealData tests with actual NBA schedule data
 func TestInitWeekScheduleWithRealData(t *testing.T) {
 	// Path to the actual schedule file
-	schedulePath := filepath.Join("..", "static", "schedule2025-2026.json")
+	schedulePath := filepath.Join("..", "..", "static", "schedule2025-2026.json")
 	
 	// Check if the file exists
 	if _, err := os.Stat(schedulePath); os.IsNotExist(err) {
@@ -236,7 +236,7 @@ func TestInitWeekScheduleWithRealData(t *testing.T) {
 
 // BenchmarkInitWeekSchedule benchmarks the single week loading performance
 func BenchmarkInitWeekSchedule(b *testing.B) {
-	schedulePath := filepath.Join("..", "static", "schedule2025-2026.json")
+	schedulePath := filepath.Join("..", "..", "static", "schedule2025-2026.json")
 	
 	// Check if the file exists
 	if _, err := os.Stat(schedulePath); os.IsNotExist(err) {
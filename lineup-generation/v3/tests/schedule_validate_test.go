@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	h "v3/helpers"
+)
+
+// Two weeks with one of every problem the validator looks for, line numbers matter so keep the layout as is
+const invalidScheduleJSON = `{
+	"1": {
+		"startDate": "10/21/2025",
+		"endDate": "10/26/2025",
+		"gameSpan": 6,
+		"games": {
+			"OKC": [0, 2, 2],
+			"HOU": [0, 6],
+			"LAL": [1]
+		}
+	},
+	"2": {
+		"startDate": "10/26/2025",
+		"endDate": "11/02/2025",
+		"gameSpan": 7,
+		"games": {
+			"OKC": [0],
+			"HOU": [1]
+		}
+	}
+}`
+
+func TestValidateSchedule(t *testing.T) {
+	errs := h.ValidateSchedule([]byte(invalidScheduleJSON))
+
+	expected := []h.ScheduleError{
+		{Kind: h.DuplicateDay, Line: 7, Week: "1", Team: "OKC", Day: 2},
+		{Kind: h.DayOutOfRange, Line: 8, Week: "1", Team: "HOU", Day: 6},
+		{Kind: h.GameSpanMismatch, Line: 12, Week: "2"},
+		{Kind: h.OverlappingWeeks, Line: 12, Week: "2"},
+		{Kind: h.MissingTeam, Line: 12, Week: "2", Team: "LAL"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d:\n%v", len(expected), len(errs), errs)
+	}
+	for i, want := range expected {
+		got := errs[i]
+		if got.Kind != want.Kind || got.Line != want.Line || got.Week != want.Week || got.Team != want.Team || got.Day != want.Day {
+			t.Errorf("Error %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	// Only the errors in week 1 are kept when asking about week 1
+	if week_errs := errs.ForWeeks(1); len(week_errs) != 2 {
+		t.Errorf("Expected 2 errors in week 1, got %v", week_errs)
+	}
+
+	// A missing team is only a warning, so it doesn't make a week invalid on its own
+	if !errs[4].Warning || errs[0].Warning {
+		t.Errorf("Expected only the missing team to be a warning, got %v", errs)
+	}
+	if week_errs := (h.ScheduleErrors{errs[4]}); week_errs.Err() != nil {
+		t.Errorf("Expected warnings to leave the schedule valid, got %v", week_errs.Err())
+	}
+}
+
+func TestValidateScheduleDuplicateKeys(t *testing.T) {
+	// The second OKC and the second week 1 would quietly replace the first ones
+	errs := h.ValidateSchedule([]byte(`{"version": 1, "weeks": {
+		"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {
+			"OKC": [{"day": 0}],
+			"OKC": [{"day": 2}]
+		}},
+		"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {
+			"OKC": [{"day": 0}]
+		}}
+	}}`))
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %d:\n%v", len(errs), errs)
+	}
+	if errs[0].Kind != h.DuplicateKey || errs[0].Line != 4 || errs[0].Week != "1" {
+		t.Errorf("Expected the second OKC on line 4, got %+v", errs[0])
+	}
+	if errs[1].Kind != h.DuplicateKey || errs[1].Line != 6 || errs[1].Week != "1" {
+		t.Errorf("Expected the second week 1 on line 6, got %+v", errs[1])
+	}
+}
+
+func TestValidateStaticSchedules(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "..", "static", h.ScheduleFilePattern))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("Expected schedule files in static, got %v %v", paths, err)
+	}
+
+	// Both servers refuse invalid weeks, so every schedule that ships has to pass
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if err := h.ValidateSchedule(data).Err(); err != nil {
+			t.Errorf("%s:\n%v", path, err)
+		}
+	}
+}
+
+func TestValidateScheduleLayouts(t *testing.T) {
+	for name, data := range map[string]string{"versioned": versionedScheduleJSON, "legacy v2": legacyV2ScheduleJSON} {
+		if errs := h.ValidateSchedule([]byte(data)); errs.Err() != nil {
+			t.Errorf("%s: expected no errors, got %v", name, errs)
+		}
+	}
+
+	// Teams in the versioned format are anchored to the line of their games
+	errs := h.ValidateSchedule([]byte(`{"version": 1, "weeks": {"1": {"startDate": "10/21/2025", "endDate": "10/26/2025", "gameSpan": 6, "games": {
+		"OKC": [{"day": 3}, {"day": 3}]
+	}}}}`))
+	if len(errs) != 1 || errs[0].Kind != h.DuplicateDay || errs[0].Line != 2 {
+		t.Errorf("Expected a duplicate day on line 2, got %v", errs)
+	}
+}
+
+func TestValidateScheduleInvalidJSON(t *testing.T) {
+	data, err := os.ReadFile("../static/invalid_json_file.json")
+	if err != nil {
+		t.Fatalf("Failed to read invalid file: %v", err)
+	}
+	errs := h.ValidateSchedule(data)
+	if len(errs) != 1 || errs[0].Kind != h.InvalidJSON || errs[0].Line != 2 {
+		t.Errorf("Expected invalid JSON on line 2, got %v", errs)
+	}
+
+	errs = h.ValidateSchedule([]byte("{\n\t\"1\": {\n\t\t\"gameSpan\": 6,,\n\t}\n}"))
+	if len(errs) != 1 || errs[0].Kind != h.InvalidJSON || errs[0].Line != 3 {
+		t.Errorf("Expected invalid JSON on line 3, got %v", errs)
+	}
+}
+
+func TestLoadWeekScheduleRejectInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	if err := os.WriteFile(path, []byte(invalidScheduleJSON), 0644); err != nil {
+		t.Fatalf("Failed to write schedule: %v", err)
+	}
+
+	// Without validation the bad week still loads
+	if _, err := h.LoadWeekSchedule(path, 2); err != nil {
+		t.Errorf("Expected week 2 to load without validation, got %v", err)
+	}
+
+	if _, err := h.LoadWeekScheduleWithOptions(path, 2, h.LoadOptions{RejectInvalid: true}); err == nil {
+		t.Error("Expected week 2 to be refused")
+	}
+	if _, err := h.LoadHorizonScheduleWithOptions(path, []int{1, 2}, h.LoadOptions{RejectInvalid: true}); err == nil {
+		t.Error("Expected the horizon to be refused")
+	}
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Files copied between the versions, each version is its own module so they can't share a package
+var sharedFiles = map[string][]string{
+	"acquisitions.go":      {"../helpers", "../../v2/data"},
+	"injury.go":            {"../helpers", "../../v2/data"},
+	"lock_mode.go":         {"../helpers", "../../v2/data"},
+	"roster_template.go":   {"../helpers", "../../v2/data"},
+	"schedule_validate.go": {"../helpers", "../../v2/data"},
+	"matching.go":          {"../helpers", "../../v2/utils", "../../v1/functions"},
+}
+
+// Function to read a Go file without the note and package clause at the top, which are the only lines that differ
+// between the copies
+func readSharedFile(t *testing.T, path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	for len(data) > 0 && !bytes.HasPrefix(data, []byte("package ")) {
+		_, data, _ = bytes.Cut(data, []byte("\n"))
+	}
+	_, body, _ := bytes.Cut(data, []byte("\n"))
+	return body
+}
+
+func TestSharedFilesInSync(t *testing.T) {
+	for name, dirs := range sharedFiles {
+		original := filepath.Join(dirs[0], name)
+		expected := readSharedFile(t, original)
+		for _, dir := range dirs[1:] {
+			copy := filepath.Join(dir, name)
+			if !bytes.Equal(readSharedFile(t, copy), expected) {
+				t.Errorf("%s differs from %s, change both copies together", copy, original)
+			}
+		}
+	}
+}
@@ -122,7 +122,7 @@ func TestInitSetupStateMetadata(t *testing.T) {
 	freeAgents := createMockFreeAgents()
 	threshold := 30.0
 
-	setup_state := h.InitSetupState(schedule, roster, freeAgents, threshold)
+	setup_state := h.InitSetupState(schedule, roster, freeAgents, threshold, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
 	setup_state.Print()
 }
 
@@ -132,7 +132,7 @@ func TestInitState(t *testing.T) {
 	freeAgents := createMockFreeAgents()
 	threshold := 30.0
 
-	setup_state := h.InitSetupState(schedule, roster, freeAgents, threshold)
-	state := h.InitState(schedule, setup_state, freeAgents)
+	setup_state := h.InitSetupState(schedule, roster, freeAgents, threshold, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	state := h.InitState(schedule, setup_state, freeAgents, h.DefaultAcquisitionLimits(schedule.GetGameSpan()))
 	state.Print()
 }
\ No newline at end of file
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	h "v3/helpers"
+)
+
+func TestBeamSearchCancelled(t *testing.T) {
+	schedule, roster, free_agents := createIRWeek()
+	setup_state := h.InitSetupState(schedule, roster, free_agents, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	root := h.InitState(schedule, setup_state, free_agents, h.DefaultAcquisitionLimits(schedule.GetGameSpan()))
+
+	// A search that's cut off before it starts makes no moves, but still brings the star back from IR
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	best, err := h.BeamSearchContext(ctx, schedule, setup_state, root, h.BeamWidth)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the search to be cancelled, got %v", err)
+	}
+	if !best.IsWithinLimits() || best.GetDay() != schedule.GetGameSpan()-1 {
+		t.Errorf("Expected a plan within the limits carried to the last day, got day %d", best.GetDay())
+	}
+	rosters := best.ToRosters(setup_state)
+	for _, roster := range rosters {
+		if len(roster.Additions) != 0 {
+			t.Errorf("Day %d: expected no pickups, got %v", roster.Day, roster.Additions)
+		}
+	}
+	if len(rosters[2].Actions) != 1 || rosters[2].Actions[0].Action != h.ActionActivate || rosters[2].Roster["C"].Name != "Injured Star" {
+		t.Errorf("Expected the star to be activated on day 2, got %v", rosters[2].Actions)
+	}
+}
+
+func TestWideningBeamSearch(t *testing.T) {
+	schedule := createStreamingSchedule()
+	free_agents := createMockFreeAgents()
+	setup_state := h.InitSetupState(schedule, createMockRoster(), free_agents, 30.0, h.DefaultRosterTemplate(), h.SlotByRestrictiveness, h.LockDaily)
+	root := h.InitState(schedule, setup_state, free_agents, h.DefaultAcquisitionLimits(schedule.GetGameSpan()))
+
+	// With time to spare every width is searched, and the plan is at least as good as the narrow beam's
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	best, err := h.WideningBeamSearch(ctx, schedule, setup_state, root)
+	if err != nil {
+		t.Fatalf("Expected the search to finish, got %v", err)
+	}
+	if narrow := h.BeamSearch(schedule, setup_state, root, h.BeamWidth); best.GetScore() < narrow.GetScore() {
+		t.Errorf("Expected at least the narrow beam's %d, got %d", narrow.GetScore(), best.GetScore())
+	}
+	if !best.IsWithinLimits() {
+		t.Error("Expected the plan to follow the acquisition limits")
+	}
+}
+
+func TestValidateTimeBudget(t *testing.T) {
+	request := h.Request{}
+	if request.ValidateTimeBudget() != nil || request.GetTimeBudget() != 0 {
+		t.Error("Expected no budget to be valid")
+	}
+	for ms, valid := range map[int]bool{250: true, 60000: true, 0: false, -1: false, 60001: false} {
+		request.TimeBudgetMs = &ms
+		if (request.ValidateTimeBudget() == nil) != valid {
+			t.Errorf("Expected a %dms budget to be valid: %v", ms, valid)
+		}
+	}
+}